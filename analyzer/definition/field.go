@@ -0,0 +1,15 @@
+package definition
+
+import "reflect"
+
+// Field struct stores information about a struct field, with its raw tag.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+// Lookup returns the value of the given key in the field tag, if present.
+func (f Field) Lookup(key string) (string, bool) {
+	return reflect.StructTag(f.Tag).Lookup(key)
+}
@@ -2,16 +2,23 @@ package definition
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"unicode"
 )
 
 // Function struct defines a Go function with its name, whether it is private, its parameters and return values.
 type Function struct {
-	Name    string  `json:"name"`
-	Private bool    `json:"-"`
-	Params  []Param `json:"params,omitempty"`
-	Returns []Param `json:"returns,omitempty"`
+	Name    string `json:"name"`
+	Private bool   `json:"-"`
+	// TypeParams holds the type parameters of a generic function, typed by their constraint.
+	TypeParams []Param `json:"typeParams,omitempty"`
+	Params     []Param `json:"params,omitempty"`
+	Returns    []Param `json:"returns,omitempty"`
+	// Calls holds the methods of package types called in the function body, by type name.
+	Calls map[string][]string `json:"calls,omitempty"`
+	// Directives holds the autofx directives documenting the function, mapped to their arguments.
+	Directives map[string]string `json:"directives,omitempty"`
 }
 
 // NewFunction method returns a new Function object.
@@ -33,6 +40,45 @@ func (f *Function) Signature() string {
 	return fmt.Sprintf("%s(%s) %s", f.Name, stringfyParam(f.Params), returns)
 }
 
+// Instantiate returns the function instantiated with the given type arguments, named like NewRepo[User]
+// and with its type parameters substituted in the parameter and return types.
+func (f Function) Instantiate(args []string) (Function, error) {
+	if len(args) != len(f.TypeParams) {
+		return f, fmt.Errorf("function %s takes %d type arguments, got %d", f.Name, len(f.TypeParams), len(args))
+	}
+
+	subst := make(map[string]string, len(args))
+	for i, tp := range f.TypeParams {
+		subst[tp.Name] = args[i]
+	}
+	instantiate := func(params []Param) []Param {
+		inst := make([]Param, len(params))
+		for i, p := range params {
+			inst[i] = Param{Name: p.Name, Type: substitute(p.Type, subst)}
+		}
+		return inst
+	}
+
+	inst := f
+	inst.Name = fmt.Sprintf("%s[%s]", f.Name, strings.Join(args, ", "))
+	inst.TypeParams = nil
+	inst.Params = instantiate(f.Params)
+	inst.Returns = instantiate(f.Returns)
+	return inst, nil
+}
+
+var identRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_.]*`)
+
+// substitute replaces the unqualified identifiers of a type found in the given map.
+func substitute(typ string, subst map[string]string) string {
+	return identRegexp.ReplaceAllStringFunc(typ, func(id string) string {
+		if t, ok := subst[id]; ok {
+			return t
+		}
+		return id
+	})
+}
+
 // IsConstructor check if the function is a constructor(starts with New).
 func (f *Function) IsConstructor() bool {
 	return strings.HasPrefix(f.Name, "New")
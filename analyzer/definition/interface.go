@@ -1,10 +1,16 @@
 package definition
 
+import "go/token"
+
 // Interface struct defines a Go interface with its name and a slice of Methods.
 type Interface struct {
 	Name            string   `json:"name"`
 	Methods         []Method `json:"methods"`
 	Implementations []string `json:"implementations"`
+	// Embeds holds the embedded interfaces, whose methods are part of Methods.
+	Embeds []string `json:"embeds,omitempty"`
+	// Directives maps the autofx directives documenting the interface to their arguments.
+	Directives map[string]string `json:"directives,omitempty"`
 }
 
 // NewInterface function initializes a new Interface struct with the given name. It sets the Methods field to an empty slice to allow methods to be added later.
@@ -20,3 +26,15 @@ func NewInterface(name string) *Interface {
 func (i Interface) Type() string {
 	return i.Name
 }
+
+// Private tells if the interface is internal to its package, either unexported or marked with //autofx:private.
+func (i Interface) Private() bool {
+	_, marked := i.Directives["private"]
+	return marked || !token.IsExported(i.Name)
+}
+
+// Decorated tells if the interface is marked with //autofx:decorate, to be wrapped by a generated decorator.
+func (i Interface) Decorated() bool {
+	_, marked := i.Directives["decorate"]
+	return marked
+}
@@ -34,30 +34,14 @@ func (m *Method) SetReceiverName(name string) {
 }
 
 // Same compares two Method structs and returns true if their definition matches. This would be useful for identifying duplicate methods.
+// Parameters and return values are compared by position and type, their names being irrelevant to the signature.
 func (m *Method) Same(check Method) bool {
 	if m.Name != check.Name {
 		return false
 	}
-	if len(m.Params) != len(check.Params) {
-		return false
-	}
-	if len(m.Returns) != len(check.Returns) {
-		return false
-	}
-
-	for i := range check.Params {
-		if !slices.Contains(m.Params, check.Params[i]) {
-			return false
-		}
-	}
-
-	for i := range check.Returns {
-		if !slices.Contains(m.Returns, check.Returns[i]) {
-			return false
-		}
-	}
 
-	return true
+	sameTypes := func(a, b Param) bool { return a.Type == b.Type }
+	return slices.EqualFunc(m.Params, check.Params, sameTypes) && slices.EqualFunc(m.Returns, check.Returns, sameTypes)
 }
 
 // Signature returns the signature of the method. This is the same as the signature of the Function struct. The receiver name is prepended to the signature. This is useful for generating documentation.
@@ -0,0 +1,67 @@
+package definition
+
+import "testing"
+
+func TestMethodSame(t *testing.T) {
+	method := func(name string, params []Param, returns ...Param) Method {
+		m := NewMethod(name)
+		m.Params = params
+		m.Returns = returns
+		return *m
+	}
+	get := method("Get", []Param{{Name: "ctx", Type: "context.Context"}, {Name: "key", Type: "string"}}, Param{Type: "string"}, Param{Type: "error"})
+
+	tests := []struct {
+		name  string
+		check Method
+		want  bool
+	}{
+		{
+			name:  "identical",
+			check: get,
+			want:  true,
+		},
+		{
+			name:  "renamed parameters",
+			check: method("Get", []Param{{Name: "c", Type: "context.Context"}, {Name: "k", Type: "string"}}, Param{Type: "string"}, Param{Name: "err", Type: "error"}),
+			want:  true,
+		},
+		{
+			name:  "unnamed parameters",
+			check: method("Get", []Param{{Type: "context.Context"}, {Type: "string"}}, Param{Type: "string"}, Param{Type: "error"}),
+			want:  true,
+		},
+		{
+			name:  "swapped parameters",
+			check: method("Get", []Param{{Name: "key", Type: "string"}, {Name: "ctx", Type: "context.Context"}}, Param{Type: "string"}, Param{Type: "error"}),
+			want:  false,
+		},
+		{
+			name:  "swapped returns",
+			check: method("Get", []Param{{Name: "ctx", Type: "context.Context"}, {Name: "key", Type: "string"}}, Param{Type: "error"}, Param{Type: "string"}),
+			want:  false,
+		},
+		{
+			name:  "missing parameter",
+			check: method("Get", []Param{{Name: "key", Type: "string"}}, Param{Type: "string"}, Param{Type: "error"}),
+			want:  false,
+		},
+		{
+			name:  "other parameter type",
+			check: method("Get", []Param{{Name: "ctx", Type: "context.Context"}, {Name: "key", Type: "int"}}, Param{Type: "string"}, Param{Type: "error"}),
+			want:  false,
+		},
+		{
+			name:  "other name",
+			check: method("Put", []Param{{Name: "ctx", Type: "context.Context"}, {Name: "key", Type: "string"}}, Param{Type: "string"}, Param{Type: "error"}),
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := get.Same(tt.check); got != tt.want {
+				t.Errorf("Same %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
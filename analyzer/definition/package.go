@@ -3,6 +3,8 @@ package definition
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 )
 
 // Package struct defines a Go package with its name and maps containing any Interfaces, Structs and Functions it contains.
@@ -24,11 +26,21 @@ import (
 //		p.Report()
 //	}
 type Package struct {
-	Name       string                `json:"name"`
-	Path       string                `json:"path"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// ImportPath is the path the package is imported with.
+	ImportPath string                `json:"importPath,omitempty"`
 	Interfaces map[string]*Interface `json:"interfaces,omitempty"`
 	Structs    map[string]*Struct    `json:"structs,omitempty"`
 	Functions  map[string]*Function  `json:"functions,omitempty"`
+	// Imports maps the local name of each imported package to its path.
+	Imports map[string]string `json:"imports,omitempty"`
+	// WireSets holds the google/wire provider sets declared by the package, if any.
+	WireSets map[string]*WireSet `json:"wireSets,omitempty"`
+	// Wiring holds the fx wiring written by hand in the package.
+	Wiring *Wiring `json:"wiring,omitempty"`
+	// Identifiers holds every top level identifier declared by the package, generated files excluded.
+	Identifiers []string `json:"identifiers,omitempty"`
 }
 
 // NewPackage function initializes a new Package struct with the given name. It initializes the type maps to empty maps to allow types to be added later.
@@ -39,9 +51,43 @@ func NewPackage(name string, path string) *Package {
 		Interfaces: make(map[string]*Interface, 0),
 		Structs:    make(map[string]*Struct, 0),
 		Functions:  make(map[string]*Function, 0),
+		Imports:    make(map[string]string, 0),
+		WireSets:   make(map[string]*WireSet, 0),
+		Wiring:     NewWiring(),
 	}
 }
 
+// Consumers returns the structs whose constructor receives the given type as a parameter, sorted by name.
+func (p *Package) Consumers(typ string) []*Struct {
+	consumers := make([]*Struct, 0)
+	for _, s := range p.Structs {
+		for _, prm := range s.Constructor.Params {
+			if prm.BaseType() == typ {
+				consumers = append(consumers, s)
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(consumers, func(a, b *Struct) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return consumers
+}
+
+// ImportsOf returns the import paths referenced by the given parameter types, indexed by their local package name.
+func (p *Package) ImportsOf(params []Param) map[string]string {
+	imports := make(map[string]string)
+	for _, prm := range params {
+		for _, q := range prm.Qualifiers() {
+			if path, ok := p.Imports[q]; ok {
+				imports[q] = path
+			}
+		}
+	}
+	return imports
+}
+
 // Print method prints a formatted report of the contents of the package including statistics on the number of each type.
 func (p *Package) Print() {
 	var (
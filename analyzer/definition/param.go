@@ -2,9 +2,12 @@ package definition
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+var qualifierRegexp = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.`)
+
 // The Param struct stores information about a single parameter:
 type Param struct {
 	Name string `json:"name,omitempty"`
@@ -37,3 +40,15 @@ func (p *Param) Equals(other Param) bool {
 func (p *Param) BaseType() string {
 	return strings.Replace(p.Type, "*", "", -1)
 }
+
+// Qualifiers returns the package names qualifying the parameter type.
+// Ex:
+//
+//	map[string]*sql.DB -> ["sql"]
+func (p *Param) Qualifiers() []string {
+	var qualifiers []string
+	for _, m := range qualifierRegexp.FindAllStringSubmatch(p.Type, -1) {
+		qualifiers = append(qualifiers, m[1])
+	}
+	return qualifiers
+}
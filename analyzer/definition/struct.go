@@ -1,10 +1,27 @@
 package definition
 
-// Struct struct stores information about a Go struct definition
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Struct struct stores information about a Go struct definition, or a named type of another kind like type Port int.
 type Struct struct {
-	Name        string   `json:"name"`
-	Methods     []Method `json:"methods,omitempty"`
+	Name    string   `json:"name"`
+	Methods []Method `json:"methods,omitempty"`
+	// Constructor is the constructor selected among the Constructors.
 	Constructor Function `json:"constructor,omitempty"`
+	// Constructors holds every New* function returning the type, by name.
+	Constructors []Function `json:"constructors,omitempty"`
+	Fields       []Field    `json:"fields,omitempty"`
+	// Underlying is the underlying type of a named type that is not a struct, empty for structs.
+	Underlying string `json:"underlying,omitempty"`
+	// TypeParams holds the type parameters of a generic struct, typed by their constraint.
+	TypeParams []Param `json:"typeParams,omitempty"`
+	// Directives maps the autofx directives documenting the struct to their arguments.
+	Directives map[string]string `json:"directives,omitempty"`
 }
 
 // NewStruct create a new Struct instance from a name. Initializes empty slices for Methods
@@ -20,6 +37,146 @@ func (s Struct) Type() string {
 	return s.Name
 }
 
+// Private tells if the struct is internal to its package, either unexported or marked with //autofx:private.
+func (s Struct) Private() bool {
+	_, marked := s.Directives["private"]
+	return marked || !token.IsExported(s.Name)
+}
+
+// Instantiations returns the type arguments of the instantiations configured with //autofx:instantiate.
+// Instantiations are separated by commas, like User,Order, unless bracketed for several type parameters,
+// like [string,User] [int,Order].
+func (s Struct) Instantiations() [][]string {
+	args, ok := s.Directives["instantiate"]
+	if !ok {
+		return nil
+	}
+
+	var insts [][]string
+	if !strings.Contains(args, "[") {
+		for _, a := range strings.Split(args, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				insts = append(insts, []string{a})
+			}
+		}
+		return insts
+	}
+
+	for _, group := range strings.Split(args, "[")[1:] {
+		group, _, _ = strings.Cut(group, "]")
+		var inst []string
+		for _, a := range strings.Split(group, ",") {
+			inst = append(inst, strings.TrimSpace(a))
+		}
+		insts = append(insts, inst)
+	}
+	return insts
+}
+
+// Config tells if the struct holds configuration loaded from the environment, either marked with
+// //autofx:config or named like *Config or *Options with env tagged fields.
+func (s Struct) Config() bool {
+	if _, marked := s.Directives["config"]; marked {
+		return true
+	}
+	if !strings.HasSuffix(s.Name, "Config") && !strings.HasSuffix(s.Name, "Options") {
+		return false
+	}
+	return slices.ContainsFunc(s.Fields, func(f Field) bool {
+		_, ok := f.Lookup("env")
+		return ok
+	})
+}
+
+// Constructor selection rules, applied in order by SelectConstructor.
+const (
+	// RuleDirective selects the constructor named by the //autofx:constructor directive of the struct.
+	RuleDirective = "directive"
+	// RuleMarker keeps the candidates marked with //autofx:constructor, if any.
+	RuleMarker = "marker"
+	// RuleFewestParams keeps the candidates with the fewest parameters.
+	RuleFewestParams = "fewestParams"
+)
+
+// DefaultConstructorRules are the rules applied when none are configured.
+var DefaultConstructorRules = []string{RuleDirective, RuleMarker, RuleFewestParams}
+
+// CheckConstructorRules reports the first unknown constructor selection rule.
+func CheckConstructorRules(rules []string) error {
+	for _, r := range rules {
+		if !slices.Contains(DefaultConstructorRules, r) {
+			return fmt.Errorf("unknown constructor rule %s, known ones are %s", r, strings.Join(DefaultConstructorRules, ", "))
+		}
+	}
+	return nil
+}
+
+// SelectConstructor picks the constructor of the struct among its candidates. The given name wins, then the
+// rules narrow the candidates down in order, the DefaultConstructorRules when nil. Candidates left tied are
+// reported as ambiguous. A struct without candidates returns an empty Function.
+func (s Struct) SelectConstructor(name string, rules []string) (Function, error) {
+	if rules == nil {
+		rules = DefaultConstructorRules
+	}
+	err := CheckConstructorRules(rules)
+	if err != nil {
+		return Function{}, err
+	}
+
+	if name != "" {
+		return s.constructorNamed(name)
+	}
+
+	candidates := s.Constructors
+	for _, r := range rules {
+		switch r {
+		case RuleDirective:
+			if name, ok := s.Directives["constructor"]; ok && name != "" {
+				return s.constructorNamed(name)
+			}
+		case RuleMarker:
+			marked := slices.DeleteFunc(slices.Clone(candidates), func(c Function) bool {
+				_, marked := c.Directives["constructor"]
+				return !marked
+			})
+			if len(marked) > 0 {
+				candidates = marked
+			}
+		case RuleFewestParams:
+			if len(candidates) < 2 {
+				continue
+			}
+			fewest := slices.MinFunc(candidates, func(a, b Function) int { return len(a.Params) - len(b.Params) })
+			candidates = slices.DeleteFunc(slices.Clone(candidates), func(c Function) bool {
+				return len(c.Params) > len(fewest.Params)
+			})
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return Function{}, nil
+	case 1:
+		return candidates[0], nil
+	}
+
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.Name
+	}
+	return Function{}, fmt.Errorf("struct %s has ambiguous constructors %s, select one with //autofx:constructor", s.Name, strings.Join(names, ", "))
+}
+
+// constructorNamed returns the candidate constructor of the given name.
+func (s Struct) constructorNamed(name string) (Function, error) {
+	for _, c := range s.Constructors {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Function{}, fmt.Errorf("constructor %s of struct %s not found", name, s.Name)
+}
+
 // Implements Checks if a struct implements an interface by comparing method names and signatures.
 func (s *Struct) Implements(iface Interface) bool {
 	for _, mtd := range iface.Methods {
@@ -35,6 +192,50 @@ func (s *Struct) Implements(iface Interface) bool {
 	return true
 }
 
+// CallsOn returns the methods of the given type called by the struct constructor and methods.
+func (s *Struct) CallsOn(typ string) []string {
+	calls := make([]string, 0)
+	fns := []Function{s.Constructor}
+	for _, m := range s.Methods {
+		fns = append(fns, m.Function)
+	}
+
+	for _, f := range fns {
+		for _, c := range f.Calls[typ] {
+			if !slices.Contains(calls, c) {
+				calls = append(calls, c)
+			}
+		}
+	}
+
+	slices.Sort(calls)
+	return calls
+}
+
+// ExtractInterface builds a new interface with the given name from a subset of the struct methods.
+// An empty subset extracts the whole method set.
+func (s *Struct) ExtractInterface(name string, methods []string) (*Interface, error) {
+	iface := NewInterface(name)
+	if len(methods) == 0 {
+		iface.Methods = append(iface.Methods, s.Methods...)
+	}
+
+	for _, n := range methods {
+		m := s.getMethodByName(n)
+		if m == nil {
+			return nil, fmt.Errorf("method %s not found in struct %s", n, s.Name)
+		}
+		iface.Methods = append(iface.Methods, *m)
+	}
+
+	slices.SortFunc(iface.Methods, func(a, b Method) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	iface.Implementations = append(iface.Implementations, s.Name)
+
+	return iface, nil
+}
+
 // getMethodByName searches the Methods slice for a method with a matching name.
 func (s *Struct) getMethodByName(name string) *Method {
 	for _, method := range s.Methods {
@@ -0,0 +1,125 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectConstructor(t *testing.T) {
+	ctor := func(name string, params int, marked bool) Function {
+		f := Function{Name: name, Params: make([]Param, params)}
+		if marked {
+			f.Directives = map[string]string{"constructor": ""}
+		}
+		return f
+	}
+	svc := func(directive string, ctors ...Function) Struct {
+		s := Struct{Name: "Service", Constructors: ctors}
+		if directive != "" {
+			s.Directives = map[string]string{"constructor": directive}
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		s      Struct
+		given  string
+		rules  []string
+		want   string
+		errMsg string
+	}{
+		{
+			name: "no candidates",
+			s:    svc(""),
+		},
+		{
+			name: "single candidate",
+			s:    svc("", ctor("NewService", 3, false)),
+			want: "NewService",
+		},
+		{
+			name:  "explicit name over directive",
+			s:     svc("NewServiceWithCache", ctor("NewService", 1, false), ctor("NewServiceWithCache", 2, false), ctor("NewServiceWithDB", 2, true)),
+			given: "NewServiceWithDB",
+			want:  "NewServiceWithDB",
+		},
+		{
+			name: "directive over marker",
+			s:    svc("NewServiceWithCache", ctor("NewService", 1, false), ctor("NewServiceWithCache", 2, false), ctor("NewServiceWithDB", 2, true)),
+			want: "NewServiceWithCache",
+		},
+		{
+			name: "marker over fewest params",
+			s:    svc("", ctor("NewService", 1, false), ctor("NewServiceWithDB", 2, true)),
+			want: "NewServiceWithDB",
+		},
+		{
+			name: "fewest params",
+			s:    svc("", ctor("NewService", 1, false), ctor("NewServiceWithCache", 2, false)),
+			want: "NewService",
+		},
+		{
+			name: "fewest params among marked",
+			s:    svc("", ctor("NewService", 0, false), ctor("NewServiceWithCache", 2, true), ctor("NewServiceWithDB", 1, true)),
+			want: "NewServiceWithDB",
+		},
+		{
+			name:   "tie on fewest params",
+			s:      svc("", ctor("NewServiceWithCache", 1, false), ctor("NewServiceWithDB", 1, false)),
+			errMsg: "struct Service has ambiguous constructors NewServiceWithCache, NewServiceWithDB",
+		},
+		{
+			name:   "fewest params disabled",
+			s:      svc("", ctor("NewService", 1, false), ctor("NewServiceWithCache", 2, false)),
+			rules:  []string{RuleDirective, RuleMarker},
+			errMsg: "ambiguous constructors NewService, NewServiceWithCache",
+		},
+		{
+			name:  "fewest params before marker",
+			s:     svc("", ctor("NewService", 1, false), ctor("NewServiceWithDB", 2, true)),
+			rules: []string{RuleFewestParams, RuleMarker},
+			want:  "NewService",
+		},
+		{
+			name:   "directive disabled",
+			s:      svc("NewServiceWithCache", ctor("NewServiceWithCache", 2, false), ctor("NewServiceWithDB", 2, false)),
+			rules:  []string{},
+			errMsg: "ambiguous constructors",
+		},
+		{
+			name:   "directive naming no candidate",
+			s:      svc("NewServiceFromEnv", ctor("NewService", 1, false)),
+			errMsg: "constructor NewServiceFromEnv of struct Service not found",
+		},
+		{
+			name:   "explicit name naming no candidate",
+			s:      svc("", ctor("NewService", 1, false)),
+			given:  "NewServiceFromEnv",
+			errMsg: "constructor NewServiceFromEnv of struct Service not found",
+		},
+		{
+			name:   "unknown rule",
+			s:      svc("", ctor("NewService", 1, false)),
+			rules:  []string{"newest"},
+			errMsg: "unknown constructor rule newest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.SelectConstructor(tt.given, tt.rules)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("error %v, want %s", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("selected %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package definition
+
+// WireSet struct defines a google/wire provider set declared as a package variable.
+type WireSet struct {
+	Name string `json:"name"`
+	// Providers holds the Go expressions of the providers.
+	Providers []string `json:"providers,omitempty"`
+	// Sets holds the package provider sets it includes.
+	Sets     []string      `json:"sets,omitempty"`
+	Bindings []WireBinding `json:"bindings,omitempty"`
+	Structs  []WireStruct  `json:"structs,omitempty"`
+	// Values holds the Go expressions of the values.
+	Values []string `json:"values,omitempty"`
+	// Unsupported holds the positioned calls that have no autofx equivalent.
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// WireBinding struct defines a wire.Bind call, binding an interface to an implementation type.
+type WireBinding struct {
+	Interface      string `json:"interface"`
+	Implementation string `json:"implementation"`
+}
+
+// WireStruct struct defines a wire.Struct call, building a struct from the injected fields.
+type WireStruct struct {
+	Type   string  `json:"type"`
+	Fields []Param `json:"fields,omitempty"`
+}
+
+// NewWireSet function initializes a new WireSet struct with the given name.
+func NewWireSet(name string) *WireSet {
+	return &WireSet{
+		Name:        name,
+		Providers:   make([]string, 0),
+		Sets:        make([]string, 0),
+		Bindings:    make([]WireBinding, 0),
+		Structs:     make([]WireStruct, 0),
+		Values:      make([]string, 0),
+		Unsupported: make([]string, 0),
+	}
+}
@@ -0,0 +1,36 @@
+package definition
+
+import "slices"
+
+// Wiring struct defines the fx wiring written by hand in a package.
+type Wiring struct {
+	// Provided holds the types made available through fx.Provide and fx.Supply, as written in the package.
+	Provided []string `json:"provided,omitempty"`
+	// Constructors counts how many times each function is passed to fx.Provide.
+	Constructors map[string]int `json:"constructors,omitempty"`
+	Invoked      []string       `json:"invoked,omitempty"`
+	Modules      []string       `json:"modules,omitempty"`
+}
+
+// NewWiring function initializes an empty Wiring struct.
+func NewWiring() *Wiring {
+	return &Wiring{
+		Provided:     make([]string, 0),
+		Constructors: make(map[string]int),
+		Invoked:      make([]string, 0),
+		Modules:      make([]string, 0),
+	}
+}
+
+// Duplicates returns the constructors provided more than once.
+func (w *Wiring) Duplicates() []string {
+	dups := make([]string, 0)
+	for c, n := range w.Constructors {
+		if n > 1 {
+			dups = append(dups, c)
+		}
+	}
+
+	slices.Sort(dups)
+	return dups
+}
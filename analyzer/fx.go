@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/log"
+	"golang.org/x/tools/go/packages"
+)
+
+const fxPkgPath = "go.uber.org/fx"
+
+// fxWiringMatch records the fx.Provide, fx.Supply, fx.Invoke and fx.Module calls written by hand in the package,
+// warning about constructors provided more than once.
+func (i *Inspector) fxWiringMatch(files []*ast.File, pkg *packages.Package, pkgdef *definition.Package) {
+	fxName := ""
+	for name, path := range pkgdef.Imports {
+		if path == fxPkgPath {
+			fxName = name
+		}
+	}
+	if fxName == "" {
+		return
+	}
+
+	w := pkgdef.Wiring
+	qualifier := types.RelativeTo(pkg.Types)
+	provide := func(typ types.Type) {
+		ts := types.TypeString(typ, qualifier)
+		if ts != "error" && !slices.Contains(w.Provided, ts) {
+			w.Provided = append(w.Provided, ts)
+		}
+	}
+
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			switch {
+			case isPkgCall(call, fxName, "Provide"):
+				for _, arg := range call.Args {
+					target, as := fxAnnotated(arg, fxName)
+					sig, ok := pkg.TypesInfo.TypeOf(target).(*types.Signature)
+					if !ok {
+						// fx.Private and the other options passed along are not constructors.
+						continue
+					}
+					w.Constructors[types.ExprString(target)]++
+
+					if len(as) > 0 {
+						for _, a := range as {
+							if tv, ok := pkg.TypesInfo.Types[a]; ok {
+								provide(tv.Type)
+							}
+						}
+						continue
+					}
+
+					for r := 0; r < sig.Results().Len(); r++ {
+						provide(sig.Results().At(r).Type())
+					}
+				}
+			case isPkgCall(call, fxName, "Supply"):
+				for _, arg := range call.Args {
+					if t := pkg.TypesInfo.TypeOf(arg); t != nil {
+						provide(t)
+					}
+				}
+			case isPkgCall(call, fxName, "Invoke"):
+				for _, arg := range call.Args {
+					target, _ := fxAnnotated(arg, fxName)
+					w.Invoked = append(w.Invoked, types.ExprString(target))
+				}
+			case isPkgCall(call, fxName, "Module") && len(call.Args) > 0:
+				if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+					w.Modules = append(w.Modules, lit.Value[1:len(lit.Value)-1])
+				}
+			}
+			return true
+		})
+	}
+
+	for _, d := range w.Duplicates() {
+		log.Warnf("constructor %s is provided %d times by hand-written fx wiring", d, w.Constructors[d])
+	}
+}
+
+// fxAnnotated unwraps an fx.Annotate call, returning the annotated function and the types of its fx.As annotations.
+// Other expressions are returned as is.
+func fxAnnotated(expr ast.Expr, fxName string) (ast.Expr, []ast.Expr) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || !isPkgCall(call, fxName, "Annotate") || len(call.Args) == 0 {
+		return expr, nil
+	}
+
+	var as []ast.Expr
+	for _, ann := range call.Args[1:] {
+		ac, ok := ann.(*ast.CallExpr)
+		if !ok || !isPkgCall(ac, fxName, "As") {
+			continue
+		}
+		for _, a := range ac.Args {
+			if nc, ok := a.(*ast.CallExpr); ok && len(nc.Args) == 1 {
+				as = append(as, nc.Args[0])
+			}
+		}
+	}
+
+	return call.Args[0], as
+}
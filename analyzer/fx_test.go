@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestFxWiring(t *testing.T) {
+	pkg, err := NewInspector().InspectPackage(filepath.Join("testdata", "fxwiring"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := pkg.Wiring
+	want := map[string]int{"NewDB": 1, "NewCache": 1}
+	if !reflect.DeepEqual(w.Constructors, want) {
+		t.Errorf("constructors %v, want %v", w.Constructors, want)
+	}
+	if dups := w.Duplicates(); len(dups) > 0 {
+		t.Errorf("duplicate constructors %v", dups)
+	}
+
+	slices.Sort(w.Provided)
+	if provided := []string{"*Cache", "*DB"}; !slices.Equal(w.Provided, provided) {
+		t.Errorf("provided %v, want %v", w.Provided, provided)
+	}
+	if !slices.Equal(w.Modules, []string{"fxwiring"}) {
+		t.Errorf("modules %v, want [fxwiring]", w.Modules)
+	}
+}
@@ -4,6 +4,13 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/jsperandio/autofx/analyzer/definition"
 	"github.com/jsperandio/autofx/analyzer/parser"
@@ -46,9 +53,22 @@ func (i *Inspector) InspectPackage(path string) (*definition.Package, error) {
 
 	pkg := loadedPackages[0]
 	pkgdef := definition.NewPackage(pkg.Name, path)
+	pkgdef.ImportPath = pkg.PkgPath
 	var mthds []*definition.Method
 
+	files := make([]*ast.File, 0, len(pkg.Syntax))
 	for _, f := range pkg.Syntax {
+		if parser.IsGenerated(f) {
+			log.Debugf("skipping generated file %s", pkg.Fset.Position(f.Pos()).Filename)
+			continue
+		}
+		files = append(files, f)
+	}
+
+	for _, f := range files {
+		i.importsMatch(f, pkg.Types, pkgdef)
+		i.identifiersMatch(f, pkgdef)
+
 		ast.Inspect(f, func(n ast.Node) bool {
 			switch spec := n.(type) {
 			case *ast.TypeSpec:
@@ -71,6 +91,13 @@ func (i *Inspector) InspectPackage(path string) (*definition.Package, error) {
 				}
 				log.Debugf("struct parse error %s", err.Error())
 
+				s, err = parser.ParseNamedType(spec)
+				if err == nil {
+					pkgdef.Structs[s.Name] = s
+					break
+				}
+				log.Debugf("named type parse error %s", err.Error())
+
 			case *ast.FuncDecl:
 
 				mthd, err := parser.ParseMethod(spec)
@@ -78,6 +105,8 @@ func (i *Inspector) InspectPackage(path string) (*definition.Package, error) {
 					log.Error("method parse error %s", err.Error())
 					break
 				}
+				mthd.Calls = i.callsMatch(spec, pkg.TypesInfo, pkg.Types)
+				mthd.Directives = parser.ParseDirectives(spec.Doc)
 
 				if mthd.ReceiverName() == "" {
 					pkgdef.Functions[mthd.Name] = &mthd.Function
@@ -95,6 +124,10 @@ func (i *Inspector) InspectPackage(path string) (*definition.Package, error) {
 
 	}
 
+	i.directivesMatch(files, pkgdef)
+	i.embedsMatch(pkg.Types, pkgdef)
+	i.wireSetsMatch(files, pkg, pkgdef)
+	i.fxWiringMatch(files, pkg, pkgdef)
 	i.methodMatch(mthds, pkgdef)
 	i.constructorMatch(pkgdef)
 	i.implementationsMatch(pkgdef)
@@ -102,6 +135,50 @@ func (i *Inspector) InspectPackage(path string) (*definition.Package, error) {
 	return pkgdef, nil
 }
 
+// InspectPackages analyzes every Go package found under the given root directory, main packages excluded.
+// Hidden, vendor and testdata directories are skipped, as well as nested modules.
+func (i *Inspector) InspectPackages(root string) ([]*definition.Package, error) {
+	var pkgs []*definition.Package
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "go.mod")); path != root && err == nil {
+			return filepath.SkipDir
+		}
+
+		sources, err := filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(sources, func(s string) bool { return !strings.HasSuffix(s, "_test.go") }) {
+			return nil
+		}
+
+		pkg, err := i.InspectPackage(path)
+		if err != nil {
+			return err
+		}
+		if pkg.Name != "main" {
+			pkgs = append(pkgs, pkg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgs, nil
+}
+
 // methodMatch matches methods parsed from the AST to the respective structs .
 func (*Inspector) methodMatch(mthds []*definition.Method, pkgdef *definition.Package) {
 	for i := 0; i < len(mthds); i++ {
@@ -115,17 +192,32 @@ func (*Inspector) methodMatch(mthds []*definition.Method, pkgdef *definition.Pac
 	}
 }
 
-// constructorMatch matches constructor functions parsed from the AST to the respective structs.
+// constructorMatch matches constructor functions parsed from the AST to the respective structs, selecting
+// the constructor of each struct by the default rules. Structs with ambiguous constructors are left without
+// one, for the generator to select by configuration.
 func (i Inspector) constructorMatch(pkg *definition.Package) {
 	for _, f := range pkg.Functions {
-		if f.IsConstructor() {
-			s, found := pkg.Structs[f.Returns[0].BaseType()]
+		if f.IsConstructor() && len(f.Returns) > 0 {
+			name, _, _ := strings.Cut(f.Returns[0].BaseType(), "[")
+			s, found := pkg.Structs[name]
 			if !found {
 				continue
 			}
-			s.Constructor = *f
+			s.Constructors = append(s.Constructors, *f)
 		}
 	}
+
+	for _, s := range pkg.Structs {
+		slices.SortFunc(s.Constructors, func(a, b definition.Function) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		ctor, err := s.SelectConstructor("", nil)
+		if err != nil {
+			log.Debugf("constructor not selected: %s", err)
+		}
+		s.Constructor = ctor
+	}
 }
 
 // implementationsMatch attach structs for the implemented interfaces
@@ -138,3 +230,191 @@ func (i Inspector) implementationsMatch(pkg *definition.Package) {
 		}
 	}
 }
+
+// importsMatch records the imports of a file in the package definition, indexed by their local name.
+func (*Inspector) importsMatch(f *ast.File, tpkg *types.Package, pkgdef *definition.Package) {
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := p[strings.LastIndex(p, "/")+1:]
+		for _, ip := range tpkg.Imports() {
+			if ip.Path() == p {
+				name = ip.Name()
+			}
+		}
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+
+		pkgdef.Imports[name] = p
+	}
+}
+
+// identifiersMatch records the identifiers declared at the file top level.
+func (*Inspector) identifiersMatch(f *ast.File, pkgdef *definition.Package) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				pkgdef.Identifiers = append(pkgdef.Identifiers, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					pkgdef.Identifiers = append(pkgdef.Identifiers, sp.Name.Name)
+				case *ast.ValueSpec:
+					for _, n := range sp.Names {
+						pkgdef.Identifiers = append(pkgdef.Identifiers, n.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// directivesMatch attaches the autofx directives documenting type declarations to the respective structs and interfaces.
+// A declaration comment applies to its types only when it declares a single one.
+func (*Inspector) directivesMatch(files []*ast.File, pkgdef *definition.Package) {
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				docs := []*ast.CommentGroup{ts.Doc}
+				if len(d.Specs) == 1 {
+					docs = append(docs, d.Doc)
+				}
+
+				directives := parser.ParseDirectives(docs...)
+				if len(directives) == 0 {
+					continue
+				}
+				if s, ok := pkgdef.Structs[ts.Name.Name]; ok {
+					s.Directives = directives
+				}
+				if i, ok := pkgdef.Interfaces[ts.Name.Name]; ok {
+					i.Directives = directives
+				}
+			}
+		}
+	}
+}
+
+// embedsMatch completes the interfaces with the methods of their embedded interfaces, local or imported,
+// and drops the constraint interfaces the parser could not tell apart.
+func (i *Inspector) embedsMatch(tpkg *types.Package, pkgdef *definition.Package) {
+	for name, ifc := range pkgdef.Interfaces {
+		tn, ok := tpkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		it, ok := tn.Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+
+		if !it.IsMethodSet() {
+			log.Debugf("skipping constraint interface %s", name)
+			delete(pkgdef.Interfaces, name)
+			continue
+		}
+		if len(ifc.Embeds) == 0 {
+			continue
+		}
+
+		for j := 0; j < it.NumMethods(); j++ {
+			fn := it.Method(j)
+			if slices.ContainsFunc(ifc.Methods, func(m definition.Method) bool { return m.Name == fn.Name() }) {
+				continue
+			}
+			ifc.Methods = append(ifc.Methods, *i.signatureMethod(fn, tpkg, pkgdef))
+		}
+	}
+}
+
+// signatureMethod builds the method definition of a typed function, qualifying the types declared
+// in other packages by their local import name, imported as needed.
+func (*Inspector) signatureMethod(fn *types.Func, tpkg *types.Package, pkgdef *definition.Package) *definition.Method {
+	qualifier := func(p *types.Package) string {
+		if p == tpkg {
+			return ""
+		}
+		for name, path := range pkgdef.Imports {
+			if path == p.Path() {
+				return name
+			}
+		}
+		if _, taken := pkgdef.Imports[p.Name()]; !taken {
+			pkgdef.Imports[p.Name()] = p.Path()
+		}
+		return p.Name()
+	}
+
+	sig := fn.Type().(*types.Signature)
+	params := func(t *types.Tuple, variadic bool) []definition.Param {
+		list := make([]definition.Param, t.Len())
+		for k := 0; k < t.Len(); k++ {
+			v := t.At(k)
+			typ := types.TypeString(v.Type(), qualifier)
+			if variadic && k == t.Len()-1 {
+				typ = "..." + strings.TrimPrefix(typ, "[]")
+			}
+			list[k] = *definition.NewParam(v.Name(), typ)
+		}
+		return list
+	}
+
+	m := definition.NewMethod(fn.Name())
+	m.Params = params(sig.Params(), sig.Variadic())
+	m.Returns = params(sig.Results(), false)
+	return m
+}
+
+// callsMatch collects the methods of package types called in a function body, indexed by type name.
+func (*Inspector) callsMatch(fn *ast.FuncDecl, info *types.Info, tpkg *types.Package) map[string][]string {
+	calls := make(map[string][]string)
+	if fn.Body == nil || info == nil {
+		return calls
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		s, ok := info.Selections[sel]
+		if !ok || s.Kind() != types.MethodVal {
+			return true
+		}
+
+		recv := s.Recv()
+		if p, ok := recv.(*types.Pointer); ok {
+			recv = p.Elem()
+		}
+
+		named, ok := recv.(*types.Named)
+		if !ok || named.Obj().Pkg() != tpkg {
+			return true
+		}
+
+		name := named.Obj().Name()
+		if !slices.Contains(calls[name], sel.Sel.Name) {
+			calls[name] = append(calls[name], sel.Sel.Name)
+		}
+		return true
+	})
+
+	return calls
+}
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEmbeddedInterfaces(t *testing.T) {
+	pkg, err := NewInspector().InspectPackage(filepath.Join("testdata", "embeds"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Number", "Int"} {
+		if _, ok := pkg.Interfaces[name]; ok {
+			t.Errorf("constraint interface %s kept", name)
+		}
+	}
+
+	store, ok := pkg.Interfaces["Store"]
+	if !ok {
+		t.Fatal("interface Store not found")
+	}
+	signatures := make([]string, len(store.Methods))
+	for i, m := range store.Methods {
+		signatures[i] = m.Function.Signature()
+	}
+	slices.Sort(signatures)
+	want := []string{
+		"Close() error",
+		"Get(ctx context.Context,key string) (string,error)",
+		"Put(key string,value string) error",
+	}
+	if !slices.Equal(signatures, want) {
+		t.Errorf("methods\n%q\nwant\n%q", signatures, want)
+	}
+
+	if !slices.Equal(store.Implementations, []string{"MemStore"}) {
+		t.Errorf("implementations %v, want MemStore", store.Implementations)
+	}
+}
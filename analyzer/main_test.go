@@ -0,0 +1,14 @@
+package analyzer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jsperandio/autofx/log"
+)
+
+func TestMain(m *testing.M) {
+	level := "error"
+	log.Init(&level)
+	os.Exit(m.Run())
+}
@@ -3,17 +3,114 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
 
 	"github.com/jsperandio/autofx/analyzer/definition"
 )
 
-// ParseStruct function parses a Go struct from an AST type specification. It validates that the type is a struct and returns a new named struct definition.
+// GeneratedHeader marks the files generated by autofx, which are left out of the analysis.
+const GeneratedHeader = "// Code generated by autofx. DO NOT EDIT."
+
+// IsGenerated checks if the file was generated by autofx.
+func IsGenerated(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			return false
+		}
+		for _, c := range cg.List {
+			if c.Text == GeneratedHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// directivePrefix starts the comment lines carrying autofx directives, like //autofx:private.
+const directivePrefix = "//autofx:"
+
+// ParseDirectives returns the autofx directives of the given comment groups, mapping each directive name to its arguments.
+func ParseDirectives(docs ...*ast.CommentGroup) map[string]string {
+	directives := make(map[string]string)
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		for _, c := range doc.List {
+			d, ok := strings.CutPrefix(c.Text, directivePrefix)
+			if !ok {
+				continue
+			}
+			name, args, _ := strings.Cut(d, " ")
+			directives[name] = strings.TrimSpace(args)
+		}
+	}
+	return directives
+}
+
+// ParseStruct function parses a Go struct from an AST type specification. It validates that the type is a struct and returns a new named struct definition along with its fields.
 func ParseStruct(typeSpec *ast.TypeSpec) (*definition.Struct, error) {
-	_, ok := typeSpec.Type.(*ast.StructType)
+	st, ok := typeSpec.Type.(*ast.StructType)
 	if !ok {
 		return nil, fmt.Errorf("type %s is not a struct", typeSpec.Name)
 	}
-	return definition.NewStruct(typeSpec.Name.Name), nil
+
+	s := definition.NewStruct(typeSpec.Name.Name)
+	if typeSpec.TypeParams != nil {
+		tps, err := ParseParams(typeSpec.TypeParams)
+		if err != nil {
+			return nil, err
+		}
+		s.TypeParams = tps
+	}
+
+	for _, f := range st.Fields.List {
+		field := definition.Field{Type: getPlainParamType(f.Type)}
+		if f.Tag != nil {
+			tag, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			field.Tag = tag
+		}
+
+		if len(f.Names) == 0 {
+			field.Name = strings.TrimPrefix(field.Type, "*")
+			field.Name = field.Name[strings.LastIndex(field.Name, ".")+1:]
+			s.Fields = append(s.Fields, field)
+			continue
+		}
+		for _, n := range f.Names {
+			field.Name = n.Name
+			s.Fields = append(s.Fields, field)
+		}
+	}
+	return s, nil
+}
+
+// ParseNamedType parses a named type that is neither a struct nor an interface, like type Port int, as a struct definition carrying its underlying type.
+// Aliases are rejected, since they declare no type of their own.
+func ParseNamedType(typeSpec *ast.TypeSpec) (*definition.Struct, error) {
+	switch typeSpec.Type.(type) {
+	case *ast.StructType, *ast.InterfaceType:
+		return nil, fmt.Errorf("type %s is not a named non-struct type", typeSpec.Name)
+	}
+	if typeSpec.Assign.IsValid() {
+		return nil, fmt.Errorf("type %s is an alias", typeSpec.Name)
+	}
+
+	s := definition.NewStruct(typeSpec.Name.Name)
+	s.Underlying = getPlainParamType(typeSpec.Type)
+	if typeSpec.TypeParams != nil {
+		tps, err := ParseParams(typeSpec.TypeParams)
+		if err != nil {
+			return nil, err
+		}
+		s.TypeParams = tps
+	}
+	return s, nil
 }
 
 // ParseFunction parses a function declaration as a function. It extracts the parameters and returns a function definition.
@@ -21,6 +118,12 @@ func ParseFunction(funcDecl *ast.FuncDecl) (*definition.Function, error) {
 	var err error
 
 	functionDef := definition.NewFunction(funcDecl.Name.Name)
+	if funcDecl.Type.TypeParams != nil {
+		functionDef.TypeParams, err = ParseParams(funcDecl.Type.TypeParams)
+		if err != nil {
+			return nil, err
+		}
+	}
 	functionDef.Params, err = ParseParams(funcDecl.Type.Params)
 	if err != nil {
 		return nil, err
@@ -34,6 +137,8 @@ func ParseFunction(funcDecl *ast.FuncDecl) (*definition.Function, error) {
 }
 
 // ParseInterface parses an interface type specification from the AST. It returns a new interface definition with their  methods.
+// Embedded interfaces are only recorded by name, their methods being resolved with the type information.
+// Constraint interfaces, holding type sets, are rejected.
 func ParseInterface(typeSpec *ast.TypeSpec) (*definition.Interface, error) {
 	interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
 	if !ok {
@@ -42,6 +147,21 @@ func ParseInterface(typeSpec *ast.TypeSpec) (*definition.Interface, error) {
 
 	interfaceDef := definition.NewInterface(typeSpec.Name.Name)
 	for _, method := range interfaceType.Methods.List {
+		if len(method.Names) == 0 {
+			switch t := method.Type.(type) {
+			case *ast.Ident:
+				if t.Name == "comparable" {
+					return nil, fmt.Errorf("type %s is a constraint interface", typeSpec.Name)
+				}
+				interfaceDef.Embeds = append(interfaceDef.Embeds, t.Name)
+			case *ast.SelectorExpr:
+				interfaceDef.Embeds = append(interfaceDef.Embeds, getPlainParamType(t))
+			default:
+				return nil, fmt.Errorf("type %s is a constraint interface", typeSpec.Name)
+			}
+			continue
+		}
+
 		ft := method.Type.(*ast.FuncType)
 
 		mtd, err := ParseMethod(ft)
@@ -108,11 +228,20 @@ func getFuncDeclReceiverName(funcDecl *ast.FuncDecl) string {
 		return ""
 	}
 
-	switch t := funcDecl.Recv.List[0].Type.(type) {
+	return receiverTypeName(funcDecl.Recv.List[0].Type)
+}
+
+// receiverTypeName returns the name of a receiver type, without pointer notation nor type parameters.
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
 	case *ast.Ident:
 		return t.Name
 	case *ast.StarExpr:
-		return t.X.(*ast.Ident).Name
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
 	default:
 		return ""
 	}
@@ -124,14 +253,18 @@ func ParseParams(fl *ast.FieldList) ([]definition.Param, error) {
 		return make([]definition.Param, 0), nil
 	}
 
-	params := make([]definition.Param, len(fl.List))
+	params := make([]definition.Param, 0, len(fl.List))
 	for i := 0; i < len(fl.List); i++ {
 		param := fl.List[i]
-		name := ""
-		if len(param.Names) > 0 {
-			name = param.Names[0].Name
+		typ := getPlainParamType(param.Type)
+		if len(param.Names) == 0 {
+			params = append(params, *definition.NewParam("", typ))
+			continue
+		}
+
+		for _, n := range param.Names {
+			params = append(params, *definition.NewParam(n.Name, typ))
 		}
-		params[i] = *definition.NewParam(name, getPlainParamType(param.Type))
 	}
 
 	return params, nil
@@ -148,6 +281,6 @@ func getPlainParamType(typ ast.Expr) string {
 	case *ast.ArrayType:
 		return "[]" + getPlainParamType(t.Elt)
 	default:
-		return ""
+		return types.ExprString(typ)
 	}
 }
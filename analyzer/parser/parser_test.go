@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"slices"
+	"testing"
+)
+
+// typeSpec parses the declaration of a single type.
+func typeSpec(t *testing.T, decl string) *ast.TypeSpec {
+	t.Helper()
+	f, err := goparser.ParseFile(token.NewFileSet(), "src.go", "package p\n\n"+decl, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+}
+
+func TestParseInterfaceEmbeds(t *testing.T) {
+	i, err := ParseInterface(typeSpec(t, `type Store interface {
+	io.Closer
+	Getter
+	Put(key string, value string) error
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"io.Closer", "Getter"}; !slices.Equal(i.Embeds, want) {
+		t.Errorf("embeds %v, want %v", i.Embeds, want)
+	}
+	if len(i.Methods) != 1 || i.Methods[0].Name != "Put" {
+		t.Errorf("methods %v, want Put only, the embedded ones being resolved by the inspector", i.Methods)
+	}
+}
+
+func TestParseInterfaceConstraints(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+	}{
+		{name: "union", decl: "type Number interface { ~int | ~float64 }"},
+		{name: "approximation", decl: "type Integer interface { ~int }"},
+		{name: "comparable", decl: "type Key interface { comparable }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInterface(typeSpec(t, tt.decl))
+			if err == nil {
+				t.Errorf("constraint interface parsed")
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package embeds
+
+import (
+	"context"
+	"io"
+)
+
+type Getter interface {
+	Get(ctx context.Context, key string) (string, error)
+}
+
+type Store interface {
+	io.Closer
+	Getter
+	Put(key string, value string) error
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+// Int embeds a type name, only told apart from an interface with the type information.
+type Int interface {
+	int
+}
+
+type MemStore struct{}
+
+func NewMemStore() *MemStore { return &MemStore{} }
+
+func (s *MemStore) Get(ctx context.Context, key string) (string, error) { return "", nil }
+
+func (s *MemStore) Put(key string, value string) error { return nil }
+
+func (s *MemStore) Close() error { return nil }
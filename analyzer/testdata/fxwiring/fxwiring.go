@@ -0,0 +1,23 @@
+package fxwiring
+
+import "go.uber.org/fx"
+
+type DB struct{}
+
+func NewDB() *DB {
+	return &DB{}
+}
+
+type Cache struct{}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Module provides the package types by hand, keeping them private to the module.
+func Module() fx.Option {
+	return fx.Module("fxwiring",
+		fx.Provide(NewDB, fx.Private),
+		fx.Provide(NewCache, fx.Private),
+	)
+}
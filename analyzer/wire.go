@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"reflect"
+	"slices"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"golang.org/x/tools/go/packages"
+)
+
+const wirePkgPath = "github.com/google/wire"
+
+// wireSetsMatch records the google/wire provider sets declared as package variables.
+func (i *Inspector) wireSetsMatch(files []*ast.File, pkg *packages.Package, pkgdef *definition.Package) {
+	wireName := ""
+	for name, path := range pkgdef.Imports {
+		if path == wirePkgPath {
+			wireName = name
+		}
+	}
+	if wireName == "" {
+		return
+	}
+
+	calls := make(map[string]*ast.CallExpr)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for n, v := range vs.Values {
+					call, ok := v.(*ast.CallExpr)
+					if ok && isPkgCall(call, wireName, "NewSet") && n < len(vs.Names) {
+						calls[vs.Names[n].Name] = call
+					}
+				}
+			}
+		}
+	}
+
+	for name, call := range calls {
+		set := definition.NewWireSet(name)
+		i.wireArgsMatch(call.Args, set, calls, wireName, pkg)
+		pkgdef.WireSets[name] = set
+	}
+}
+
+// wireArgsMatch translates the arguments of a wire.NewSet call into the set definition.
+func (i *Inspector) wireArgsMatch(args []ast.Expr, set *definition.WireSet, sets map[string]*ast.CallExpr, wireName string, pkg *packages.Package) {
+	unsupported := func(n ast.Node, msg string) {
+		set.Unsupported = append(set.Unsupported, fmt.Sprintf("%s: %s", pkg.Fset.Position(n.Pos()), msg))
+	}
+
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case *ast.CallExpr:
+			switch {
+			case isPkgCall(a, wireName, "NewSet"):
+				i.wireArgsMatch(a.Args, set, sets, wireName, pkg)
+			case isPkgCall(a, wireName, "Bind") && len(a.Args) == 2:
+				set.Bindings = append(set.Bindings, definition.WireBinding{
+					Interface:      newArgType(a.Args[0]),
+					Implementation: newArgType(a.Args[1]),
+				})
+			case isPkgCall(a, wireName, "Struct") && len(a.Args) > 0:
+				ws, err := wireStruct(a, pkg)
+				if err != nil {
+					unsupported(a, err.Error())
+					continue
+				}
+				set.Structs = append(set.Structs, ws)
+			case isPkgCall(a, wireName, "Value") && len(a.Args) == 1:
+				set.Values = append(set.Values, exprSource(a.Args[0], pkg))
+			default:
+				unsupported(a, fmt.Sprintf("%s is not supported", types.ExprString(a.Fun)))
+			}
+		case *ast.Ident:
+			if _, ok := sets[a.Name]; ok {
+				set.Sets = append(set.Sets, a.Name)
+				continue
+			}
+			set.Providers = append(set.Providers, a.Name)
+		case *ast.SelectorExpr:
+			if _, ok := pkg.TypesInfo.Uses[a.Sel].(*types.Var); ok {
+				unsupported(a, fmt.Sprintf("provider set %s from another package is not supported", types.ExprString(a)))
+				continue
+			}
+			set.Providers = append(set.Providers, types.ExprString(a))
+		default:
+			unsupported(a, fmt.Sprintf("argument %s is not supported", types.ExprString(a)))
+		}
+	}
+}
+
+// wireStruct translates a wire.Struct call, resolving the injected fields through the type information.
+func wireStruct(call *ast.CallExpr, pkg *packages.Package) (definition.WireStruct, error) {
+	ws := definition.WireStruct{
+		Type:   newArgType(call.Args[0]),
+		Fields: make([]definition.Param, 0),
+	}
+
+	tv, ok := pkg.TypesInfo.Types[call.Args[0]]
+	if !ok {
+		return ws, fmt.Errorf("struct %s type not resolved", ws.Type)
+	}
+
+	ptr, ok := tv.Type.(*types.Pointer)
+	if !ok {
+		return ws, fmt.Errorf("struct %s is not built from new", ws.Type)
+	}
+
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+	if !ok {
+		return ws, fmt.Errorf("%s is not a struct", ws.Type)
+	}
+
+	var names []string
+	for _, a := range call.Args[1:] {
+		lit, ok := a.(*ast.BasicLit)
+		if !ok {
+			return ws, fmt.Errorf("struct %s field %s is not a literal", ws.Type, types.ExprString(a))
+		}
+		names = append(names, lit.Value[1:len(lit.Value)-1])
+	}
+
+	qualifier := types.RelativeTo(pkg.Types)
+	for n := 0; n < st.NumFields(); n++ {
+		fld := st.Field(n)
+		all := slices.Contains(names, "*") && reflect.StructTag(st.Tag(n)).Get("wire") != "-"
+		if !all && !slices.Contains(names, fld.Name()) {
+			continue
+		}
+		ws.Fields = append(ws.Fields, *definition.NewParam(fld.Name(), types.TypeString(fld.Type(), qualifier)))
+	}
+
+	return ws, nil
+}
+
+// exprSource returns the source code of an expression as formatted by gofmt.
+func exprSource(expr ast.Expr, pkg *packages.Package) string {
+	var buf bytes.Buffer
+	err := format.Node(&buf, pkg.Fset, expr)
+	if err != nil {
+		return types.ExprString(expr)
+	}
+	return buf.String()
+}
+
+// isPkgCall checks if the call is the given function of the package imported under pkgName.
+func isPkgCall(call *ast.CallExpr, pkgName string, fn string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == pkgName && sel.Sel.Name == fn
+}
+
+// newArgType returns the type of a new(T) expression, or the expression itself when it is not a new call.
+func newArgType(expr ast.Expr) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return types.ExprString(expr)
+	}
+	if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "new" {
+		return types.ExprString(expr)
+	}
+	return types.ExprString(call.Args[0])
+}
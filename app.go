@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/jsperandio/autofx/analyzer"
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/config"
+	"github.com/jsperandio/autofx/generator"
+	"github.com/jsperandio/autofx/log"
+)
+
+// app runs the app command, generating the module of every package found under the package path and
+// bootstrapping their composition root. An existing composition root is never overwritten.
+func app() {
+	cfg := loadConfig()
+
+	dir := *outFlag
+	if dir == "" {
+		dir = filepath.Join(*pkgPathFlag, "cmd", "app")
+	}
+
+	f, err := os.Stat(filepath.Join(dir, "main.go"))
+	if err == nil && !f.IsDir() {
+		log.Fatal("composition root already exists in ", dir)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatal(err)
+	}
+
+	pkgs, err := analyzer.NewInspector().InspectPackages(*pkgPathFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gen := generator.NewAppGenerator(pkgs, dir, cfg.Generator, packageOptions(pkgs))
+	err = gen.GeneratePackages()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	root, err := gen.Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = root.Save()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("composition root of %d packages written to %s/%s", len(pkgs), root.Path, root.Name)
+}
+
+// packageOptions loads the configuration file of every package having its own, by import path.
+// The other packages are generated with the root configuration, as is the root package when given one by flag.
+func packageOptions(pkgs []*definition.Package) map[string]generator.Options {
+	root, err := filepath.Abs(*pkgPathFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := make(map[string]generator.Options)
+	for _, pkg := range pkgs {
+		dir, err := filepath.Abs(pkg.Path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if dir == root && *configFlag != "" {
+			continue
+		}
+
+		path := filepath.Join(dir, config.DefaultFileName)
+		_, err = os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts[pkg.ImportPath] = loadConfigFile(path).Generator
+	}
+	return opts
+}
@@ -0,0 +1,212 @@
+// Package compose builds fx applications at runtime from the registries generated by autofx, so a deployment
+// can be reconfigured by a composition file rather than by regenerating its wiring.
+//
+// Every generated package with a registry exposes a Register function adding its providers to a Registry,
+// named after the package import path and the provided struct like example.com/app/store.PostgresStore, along
+// with its runtime selected interfaces, named like example.com/app/store.Store. As long as no other registered
+// package has the same name, the package name alone also does, like store.PostgresStore. A composition file
+// then lists the components to include and the keys of the selected interfaces:
+//
+//	components:
+//	  - store.PostgresStore
+//	  - api.Server
+//	bindings:
+//	  store.Store: pg
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
+)
+
+// Registry maps stable names to the provider modules and interface selectors of the generated packages.
+type Registry struct {
+	components map[string]entry
+	bindings   map[string]binding
+	// aliases maps the names qualified by package name to the ones qualified by import path.
+	aliases map[string][]string
+	err     error
+}
+
+type entry struct {
+	path   string
+	option fx.Option
+}
+
+type binding struct {
+	path     string
+	selectFn func(key string) fx.Option
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		components: make(map[string]entry),
+		bindings:   make(map[string]binding),
+		aliases:    make(map[string][]string),
+	}
+}
+
+// Component registers the provider module of a struct of the package of the given import path and name,
+// named path.name, and pkg.name unless another package of the same name registers it too.
+func (r *Registry) Component(path, pkg, name string, o fx.Option) {
+	key := path + "." + name
+	if r.registered(key) {
+		return
+	}
+	r.components[key] = entry{path: path, option: o}
+	r.aliases[pkg+"."+name] = append(r.aliases[pkg+"."+name], key)
+}
+
+// Binding registers the selector of an interface of the package of the given import path and name, providing
+// the implementation of a key, named path.name, and pkg.name unless another package of the same name
+// registers it too.
+func (r *Registry) Binding(path, pkg, name string, selectFn func(key string) fx.Option) {
+	key := path + "." + name
+	if r.registered(key) {
+		return
+	}
+	r.bindings[key] = binding{path: path, selectFn: selectFn}
+	r.aliases[pkg+"."+name] = append(r.aliases[pkg+"."+name], key)
+}
+
+// registered tells if the name is already taken, recording the error reported by Build if so.
+func (r *Registry) registered(name string) bool {
+	_, isComponent := r.components[name]
+	_, isBinding := r.bindings[name]
+	if (isComponent || isBinding) && r.err == nil {
+		r.err = fmt.Errorf("compose: %s registered twice", name)
+	}
+	return isComponent || isBinding
+}
+
+// resolve returns the name qualified by import path of a registered name, given either qualified by import
+// path or by package name. It is empty for unknown names, and an error reports the ambiguous ones.
+func (r *Registry) resolve(name string) (string, error) {
+	_, isComponent := r.components[name]
+	_, isBinding := r.bindings[name]
+	if isComponent || isBinding {
+		return name, nil
+	}
+
+	keys := r.aliases[name]
+	switch len(keys) {
+	case 0:
+		return "", nil
+	case 1:
+		return keys[0], nil
+	}
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	return "", fmt.Errorf("compose: %s is ambiguous, use one of %s", name, strings.Join(sorted, ", "))
+}
+
+// Names returns the registered component and binding names, sorted, each qualified by package name unless
+// ambiguous, and by import path otherwise.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.components)+len(r.bindings))
+	for alias, keys := range r.aliases {
+		if len(keys) == 1 {
+			names = append(names, alias)
+			continue
+		}
+		names = append(names, keys...)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Composition lists the registered components to include in an application, and the keys selecting the
+// implementation of the registered bindings.
+type Composition struct {
+	Components []string          `json:"components" yaml:"components"`
+	Bindings   map[string]string `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+}
+
+// Load reads a composition file, decoded as YAML when its extension is .yaml or .yml and as JSON otherwise.
+func Load(path string) (*Composition, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Composition{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, c)
+	default:
+		err = json.Unmarshal(raw, c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compose: %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Build returns the option composing the components and bindings of the composition, each package in its
+// own fx.Module named after its import path. Unknown and ambiguous names fail the application start.
+func (r *Registry) Build(c *Composition) fx.Option {
+	if r.err != nil {
+		return fx.Error(r.err)
+	}
+
+	byPkg := make(map[string][]fx.Option)
+	for _, name := range c.Components {
+		key, err := r.resolve(name)
+		if err != nil {
+			return fx.Error(err)
+		}
+		e, ok := r.components[key]
+		if !ok {
+			return fx.Error(r.unknown("component", name))
+		}
+		byPkg[e.path] = append(byPkg[e.path], e.option)
+	}
+	for _, name := range sortedKeys(c.Bindings) {
+		key, err := r.resolve(name)
+		if err != nil {
+			return fx.Error(err)
+		}
+		b, ok := r.bindings[key]
+		if !ok {
+			return fx.Error(r.unknown("binding", name))
+		}
+		byPkg[b.path] = append(byPkg[b.path], b.selectFn(c.Bindings[name]))
+	}
+
+	modules := make([]fx.Option, 0, len(byPkg))
+	for _, pkg := range sortedKeys(byPkg) {
+		modules = append(modules, fx.Module(pkg, byPkg[pkg]...))
+	}
+	return fx.Options(modules...)
+}
+
+// BuildFile loads the composition file at the given path and builds it, failing the application start
+// if the file cannot be read.
+func (r *Registry) BuildFile(path string) fx.Option {
+	c, err := Load(path)
+	if err != nil {
+		return fx.Error(err)
+	}
+	return r.Build(c)
+}
+
+func (r *Registry) unknown(kind, name string) error {
+	return fmt.Errorf("compose: unknown %s %s, registered: %s", kind, name, strings.Join(r.Names(), ", "))
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
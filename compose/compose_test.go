@@ -0,0 +1,298 @@
+package compose
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/fx"
+)
+
+type Store interface{ Name() string }
+
+type memStore struct{}
+
+func (memStore) Name() string { return "mem" }
+
+type pgStore struct{}
+
+func (pgStore) Name() string { return "pg" }
+
+type Server struct{ store Store }
+
+// testRegistry registers a store package selecting its Store by key, and an api package serving it.
+func testRegistry() *Registry {
+	r := NewRegistry()
+	r.Component("example.com/app/api", "api", "Server", fx.Provide(func(s Store) *Server { return &Server{store: s} }))
+	r.Binding("example.com/app/store", "store", "Store", func(key string) fx.Option {
+		switch key {
+		case "mem", "":
+			return fx.Provide(func() Store { return memStore{} })
+		case "pg":
+			return fx.Provide(func() Store { return pgStore{} })
+		}
+		return fx.Error(errors.New("unknown store " + key))
+	})
+	return r
+}
+
+func TestNames(t *testing.T) {
+	got := testRegistry().Names()
+	want := []string{"api.Server", "store.Store"}
+	if !slices.Equal(got, want) {
+		t.Errorf("names %v, want %v", got, want)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *Registry)
+		c        Composition
+		store    string
+		err      string
+	}{
+		{
+			name:  "selected implementation",
+			c:     Composition{Components: []string{"api.Server"}, Bindings: map[string]string{"store.Store": "pg"}},
+			store: "pg",
+		},
+		{
+			name:  "default implementation",
+			c:     Composition{Components: []string{"api.Server"}, Bindings: map[string]string{"store.Store": ""}},
+			store: "mem",
+		},
+		{
+			name:  "names qualified by import path",
+			c:     Composition{Components: []string{"example.com/app/api.Server"}, Bindings: map[string]string{"example.com/app/store.Store": "pg"}},
+			store: "pg",
+		},
+		{
+			name: "unknown component",
+			c:    Composition{Components: []string{"api.Client"}},
+			err:  "compose: unknown component api.Client, registered: api.Server, store.Store",
+		},
+		{
+			name: "unknown binding",
+			c:    Composition{Components: []string{"api.Server"}, Bindings: map[string]string{"store.Cache": "mem"}},
+			err:  "compose: unknown binding store.Cache, registered: api.Server, store.Store",
+		},
+		{
+			name: "binding given as component",
+			c:    Composition{Components: []string{"store.Store"}},
+			err:  "compose: unknown component store.Store",
+		},
+		{
+			name: "unknown key",
+			c:    Composition{Components: []string{"api.Server"}, Bindings: map[string]string{"store.Store": "redis"}},
+			err:  "unknown store redis",
+		},
+		{
+			name:     "component registered twice",
+			register: func(r *Registry) { r.Component("example.com/app/api", "api", "Server", fx.Options()) },
+			c:        Composition{Components: []string{"api.Server"}},
+			err:      "compose: example.com/app/api.Server registered twice",
+		},
+		{
+			name: "binding registered twice",
+			register: func(r *Registry) {
+				r.Binding("example.com/app/store", "store", "Store", func(string) fx.Option { return fx.Options() })
+			},
+			c:   Composition{Components: []string{"api.Server"}},
+			err: "compose: example.com/app/store.Store registered twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testRegistry()
+			if tt.register != nil {
+				tt.register(r)
+			}
+
+			var s *Server
+			opts := []fx.Option{r.Build(&tt.c), fx.NopLogger}
+			if tt.err == "" {
+				opts = append(opts, fx.Populate(&s))
+			}
+			err := fx.New(opts...).Err()
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.store.Name(); got != tt.store {
+				t.Errorf("store %s, want %s", got, tt.store)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	want := &Composition{
+		Components: []string{"api.Server"},
+		Bindings:   map[string]string{"store.Store": "pg"},
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    *Composition
+		err     string
+	}{
+		{
+			name:    "yaml",
+			file:    "app.yaml",
+			content: "components:\n  - api.Server\nbindings:\n  store.Store: pg\n",
+			want:    want,
+		},
+		{
+			name:    "yml",
+			file:    "app.YML",
+			content: "components: [api.Server]\nbindings: {store.Store: pg}\n",
+			want:    want,
+		},
+		{
+			name:    "json",
+			file:    "app.json",
+			content: `{"components": ["api.Server"], "bindings": {"store.Store": "pg"}}`,
+			want:    want,
+		},
+		{
+			name:    "json without extension",
+			file:    "composition",
+			content: `{"components": ["api.Server"], "bindings": {"store.Store": "pg"}}`,
+			want:    want,
+		},
+		{
+			name:    "components only",
+			file:    "app.yaml",
+			content: "components:\n  - api.Server\n",
+			want:    &Composition{Components: []string{"api.Server"}},
+		},
+		{
+			name:    "bad yaml",
+			file:    "app.yaml",
+			content: "components:\n  - api.Server\n bindings: [",
+			err:     "compose: ",
+		},
+		{
+			name:    "yaml of the wrong shape",
+			file:    "app.yaml",
+			content: "components: api.Server\n",
+			err:     "compose: ",
+		},
+		{
+			name:    "bad json",
+			file:    "app.json",
+			content: `{"components": ["api.Server"`,
+			err:     "compose: ",
+		},
+		{
+			name:    "yaml read as json",
+			file:    "app.txt",
+			content: "components:\n  - api.Server\n",
+			err:     "compose: ",
+		},
+		{
+			name: "missing file",
+			file: "missing.yaml",
+			err:  "no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if tt.content != "" {
+				err := os.WriteFile(path, []byte(tt.content), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := Load(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("composition %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	err := os.WriteFile(path, []byte("components: [api.Server]\nbindings: {store.Store: pg}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s *Server
+	err = fx.New(testRegistry().BuildFile(path), fx.Populate(&s), fx.NopLogger).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.store.Name() != "pg" {
+		t.Errorf("store %s, want pg", s.store.Name())
+	}
+
+	err = fx.New(testRegistry().BuildFile(filepath.Join(dir, "missing.yaml")), fx.NopLogger).Err()
+	if err == nil {
+		t.Error("missing composition file did not fail the application")
+	}
+}
+
+func TestSharedPackageName(t *testing.T) {
+	r := testRegistry()
+	r.Component("example.com/app/internal/a/config", "config", "Settings", fx.Supply(memStore{}))
+	r.Component("example.com/app/internal/b/config", "config", "Settings", fx.Supply(pgStore{}))
+
+	want := []string{
+		"api.Server",
+		"example.com/app/internal/a/config.Settings",
+		"example.com/app/internal/b/config.Settings",
+		"store.Store",
+	}
+	if got := r.Names(); !slices.Equal(got, want) {
+		t.Errorf("names %v, want %v", got, want)
+	}
+
+	var (
+		a memStore
+		b pgStore
+	)
+	err := fx.New(
+		r.Build(&Composition{Components: []string{
+			"example.com/app/internal/a/config.Settings",
+			"example.com/app/internal/b/config.Settings",
+		}}),
+		fx.Populate(&a, &b),
+		fx.NopLogger,
+	).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fx.New(r.Build(&Composition{Components: []string{"config.Settings"}}), fx.NopLogger).Err()
+	wantErr := "compose: config.Settings is ambiguous, use one of example.com/app/internal/a/config.Settings, example.com/app/internal/b/config.Settings"
+	if err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("error %v, want %s", err, wantErr)
+	}
+}
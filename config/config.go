@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/jsperandio/autofx/generator"
+)
+
+// DefaultFileName is the configuration file looked up in the package directory when none is given.
+const DefaultFileName = "autofx.json"
+
+// Config is the autofx configuration file, holding the options of each stage.
+//
+// Example:
+//
+//	{
+//	  "generator": {
+//	    "backend": "fx",
+//	    "assertions": true,
+//	    "templateDir": "templates"
+//	  }
+//	}
+type Config struct {
+	Generator generator.Options `json:"generator"`
+}
+
+// Load reads the configuration file at path. A missing file results in the default configuration.
+// Relative template paths are resolved against the configuration file directory.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	cfg.Generator.TemplateDir = resolve(dir, cfg.Generator.TemplateDir)
+	for name, p := range cfg.Generator.Templates {
+		cfg.Generator.Templates[name] = resolve(dir, p)
+	}
+
+	return cfg, nil
+}
+
+// AddBindings adds interface bindings, from interface to struct, to the generator bindings of the configuration
+// file at path, creating it when missing. Only the generator bindings are changed, the rest of the file being
+// kept as is, although reindented. The interfaces the file already binds to another struct are left untouched
+// and returned.
+func AddBindings(path string, bindings map[string]string) ([]string, error) {
+	doc := make(map[string]json.RawMessage)
+	gen := make(map[string]json.RawMessage)
+	bound := make(map[string]string, len(bindings))
+
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		err = json.Unmarshal(b, &doc)
+		if err == nil && doc["generator"] != nil {
+			err = json.Unmarshal(doc["generator"], &gen)
+		}
+		if err == nil && gen["bindings"] != nil {
+			err = json.Unmarshal(gen["bindings"], &bound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	if doc == nil {
+		doc = make(map[string]json.RawMessage)
+	}
+	if gen == nil {
+		gen = make(map[string]json.RawMessage)
+	}
+	if bound == nil {
+		bound = make(map[string]string, len(bindings))
+	}
+
+	var kept []string
+	for ifc, impl := range bindings {
+		if b, ok := bound[ifc]; ok && b != impl {
+			kept = append(kept, ifc)
+			continue
+		}
+		bound[ifc] = impl
+	}
+	slices.Sort(kept)
+
+	gen["bindings"], err = json.Marshal(bound)
+	if err != nil {
+		return nil, err
+	}
+	doc["generator"], err = json.Marshal(gen)
+	if err != nil {
+		return nil, err
+	}
+	b, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return kept, os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// resolve joins a relative path to the given directory.
+func resolve(dir string, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAddBindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		kept    []string
+	}{
+		{
+			name: "missing file",
+			want: `{"generator": {"bindings": {"Reader": "File", "Store": "MemStore"}}}`,
+		},
+		{
+			name:    "other keys kept",
+			content: `{"plugin": {"out": "docs"}, "generator": {"backend": "wire", "custom": [1, 2], "bindings": {"Cache": "Redis"}}}`,
+			want:    `{"plugin": {"out": "docs"}, "generator": {"backend": "wire", "custom": [1, 2], "bindings": {"Cache": "Redis", "Reader": "File", "Store": "MemStore"}}}`,
+		},
+		{
+			name:    "bound to another struct",
+			content: `{"generator": {"bindings": {"Store": "DiskStore"}}}`,
+			want:    `{"generator": {"bindings": {"Reader": "File", "Store": "DiskStore"}}}`,
+			kept:    []string{"Store"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFileName)
+			if tt.content != "" {
+				err := os.WriteFile(path, []byte(tt.content), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			kept, err := AddBindings(path, map[string]string{"Store": "MemStore", "Reader": "File"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(kept, tt.kept) {
+				t.Errorf("kept %v, want %v", kept, tt.kept)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want any
+			err = json.Unmarshal(b, &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = json.Unmarshal([]byte(tt.want), &want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("config is %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddBindingsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	err := os.WriteFile(path, []byte(`{"generator": []}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AddBindings(path, map[string]string{"Store": "MemStore"})
+	if err == nil {
+		t.Fatal("invalid configuration file overwritten")
+	}
+}
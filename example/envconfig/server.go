@@ -0,0 +1,17 @@
+package envconfig
+
+import "fmt"
+
+type Server struct {
+	cfg *ServerConfig
+}
+
+func NewServer(cfg *ServerConfig) *Server {
+	return &Server{
+		cfg: cfg,
+	}
+}
+
+func (s *Server) Describe() string {
+	return fmt.Sprintf("listening on %s, timeout %s", s.cfg.Addr, s.cfg.Timeout)
+}
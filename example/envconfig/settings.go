@@ -0,0 +1,10 @@
+package envconfig
+
+import "time"
+
+// ServerConfig has no constructor, so autofx loads it from the environment.
+type ServerConfig struct {
+	Addr    string        `env:"SERVER_ADDR" default:":8080"`
+	Timeout time.Duration `env:"SERVER_TIMEOUT" default:"5s"`
+	Debug   bool          `env:"SERVER_DEBUG"`
+}
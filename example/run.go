@@ -5,6 +5,7 @@ import (
 	"time"
 )
 
+//autofx:invoke
 func Run(s Something) {
 	ticker := time.NewTicker(time.Second * 2)
 	exit := make(chan struct{})
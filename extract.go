@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/generator"
+	"github.com/jsperandio/autofx/log"
+)
+
+// extract runs the extract command, printing the extracted interface and the consumers report.
+// The package is left untouched unless the write flag is set.
+func extract(def *definition.Package) {
+	var methods []string
+	if *methodsFlag != "" {
+		methods = strings.Split(*methodsFlag, ",")
+	}
+
+	ext, err := generator.NewExtractor(def).Extract(*structFlag, *ifaceFlag, methods, *consumerFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(string(ext.File.Content))
+
+	for _, c := range ext.Switchable {
+		log.Infof("consumer %s could switch from %s to %s", c, *structFlag, *ifaceFlag)
+	}
+	blocked := make([]string, 0, len(ext.Blocked))
+	for c := range ext.Blocked {
+		blocked = append(blocked, c)
+	}
+	slices.Sort(blocked)
+	for _, c := range blocked {
+		log.Infof("consumer %s also calls %s on %s", c, strings.Join(ext.Blocked[c], ", "), *structFlag)
+	}
+
+	if !*writeFlag {
+		return
+	}
+
+	_, err = ext.File.Save()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("interface %s written to %s/%s", *ifaceFlag, ext.File.Path, ext.File.Name)
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+)
+
+const appFileName = "main.go"
+
+var typeIdentRegexp = regexp.MustCompile(`(?:([A-Za-z_][A-Za-z0-9_]*)\.)?([A-Za-z_][A-Za-z0-9_]*)`)
+
+// AppGenerator bootstraps the composition root of an application from its analyzed packages.
+type AppGenerator struct {
+	Packages []*definition.Package
+	Dir      string
+	Options  Options
+	// PackageOptions holds the options of the packages configured on their own, by import path,
+	// the other packages sharing the root Options.
+	PackageOptions map[string]Options
+}
+
+// NewAppGenerator returns a new AppGenerator writing the composition root into the given directory.
+func NewAppGenerator(pkgs []*definition.Package, dir string, opts Options, pkgOpts map[string]Options) *AppGenerator {
+	return &AppGenerator{
+		Packages:       pkgs,
+		Dir:            dir,
+		Options:        opts,
+		PackageOptions: pkgOpts,
+	}
+}
+
+// GeneratePackages generates and saves the module of every package with its own options, for the composition
+// root to compose them.
+func (a *AppGenerator) GeneratePackages() error {
+	for _, pkg := range a.Packages {
+		err := NewGenerator(pkg, a.optionsOf(pkg)).Generate()
+		if err != nil {
+			return fmt.Errorf("package %s: %w", pkg.ImportPath, err)
+		}
+	}
+	return nil
+}
+
+// Generate builds the main.go of the composition root: an fx application composed of every package module,
+// invoking the functions marked with //autofx:invoke and standing in for the types no package provides.
+// The file is formatted but not saved.
+func (a *AppGenerator) Generate() (*File, error) {
+	err := checkTemplates(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	for _, opts := range a.PackageOptions {
+		err = checkTemplates(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs := slices.Clone(a.Packages)
+	slices.SortFunc(pkgs, func(x, y *definition.Package) int {
+		return strings.Compare(x.ImportPath, y.ImportPath)
+	})
+
+	graphs := make([]*Graph, len(pkgs))
+	provided := make(map[string]bool)
+	for i, pkg := range pkgs {
+		g, err := NewGraph(pkg, a.optionsOf(pkg))
+		if err != nil {
+			return nil, err
+		}
+		graphs[i] = g
+
+		for _, typ := range append(g.Public(), pkg.Wiring.Provided...) {
+			provided[canonicalType(typ, pkg)] = true
+		}
+	}
+
+	imports := newAppImports("fx", "go.uber.org/fx", "fxevent", "go.uber.org/fx/fxevent", "os", "os")
+	ad := tmpl.AppData{}
+	for i, pkg := range pkgs {
+		g := graphs[i]
+
+		var modules []string
+		if len(g.Providers) > 0 {
+			modules = append(modules, g.ModuleName)
+		}
+		if g.ModuleName != defaultModuleName && slices.Contains(pkg.Identifiers, defaultModuleName) {
+			modules = append(modules, defaultModuleName)
+		}
+		if g.DecoratorsName != "" {
+			modules = append(modules, g.DecoratorsName)
+		}
+		for _, m := range modules {
+			ad.Modules = append(ad.Modules, imports.alias(pkg.ImportPath, pkg.Name)+"."+m)
+		}
+
+		for _, name := range sortedKeys(pkg.Functions) {
+			if _, ok := pkg.Functions[name].Directives["invoke"]; ok {
+				ad.Invokes = append(ad.Invokes, imports.alias(pkg.ImportPath, pkg.Name)+"."+name)
+			}
+		}
+
+		for _, r := range g.External() {
+			key := canonicalType(r.Type, pkg)
+			if provided[key] {
+				continue
+			}
+			provided[key] = true
+
+			ad.Requirements = append(ad.Requirements, resolveType(r.Type, pkg, func(path, name, local string) string {
+				return imports.alias(path, local) + "." + name
+			}))
+		}
+	}
+
+	if len(ad.Requirements) > 0 {
+		imports.alias("errors", "errors")
+	}
+	ad.Imports = importList(imports.names)
+
+	t, err := loadTemplate(a.Options, "AppMain", tmpl.AppMain)
+	if err != nil {
+		return nil, err
+	}
+
+	f := NewFile(appFileName, a.Dir, nil)
+	err = t.Execute(f, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.Format()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// optionsOf returns the options the given package is generated with.
+func (a *AppGenerator) optionsOf(pkg *definition.Package) Options {
+	if opts, ok := a.PackageOptions[pkg.ImportPath]; ok {
+		return opts
+	}
+	return a.Options
+}
+
+// appImports holds the imports of the composition root, indexed by their local name.
+type appImports struct {
+	names  map[string]string
+	byPath map[string]string
+}
+
+// newAppImports returns the imports of the composition root, starting with the given name and path pairs.
+func newAppImports(pairs ...string) *appImports {
+	ai := &appImports{
+		names:  make(map[string]string),
+		byPath: make(map[string]string),
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		ai.alias(pairs[i+1], pairs[i])
+	}
+	return ai
+}
+
+// alias imports the given path, returning its local name: the given name unless taken by another path.
+func (ai *appImports) alias(path string, name string) string {
+	if a, ok := ai.byPath[path]; ok {
+		return a
+	}
+
+	a := name
+	for i := 2; ai.names[a] != ""; i++ {
+		a = fmt.Sprintf("%s%d", name, i)
+	}
+	ai.names[a] = path
+	ai.byPath[path] = a
+	return a
+}
+
+// canonicalType returns a type declared in the given package with its identifiers qualified by import path,
+// comparable across packages.
+func canonicalType(typ string, pkg *definition.Package) string {
+	return resolveType(typ, pkg, func(path, name, _ string) string {
+		return path + "." + name
+	})
+}
+
+// resolveType rewrites the identifiers of a type declared in the given package, replacing each declared
+// or imported one by the result of fn for its import path, name and local package name.
+// Predeclared identifiers and keywords are left untouched.
+func resolveType(typ string, pkg *definition.Package, fn func(path, name, local string) string) string {
+	return typeIdentRegexp.ReplaceAllStringFunc(typ, func(m string) string {
+		sub := typeIdentRegexp.FindStringSubmatch(m)
+		q, name := sub[1], sub[2]
+
+		if q != "" {
+			path, ok := pkg.Imports[q]
+			if !ok {
+				return m
+			}
+			return fn(path, name, q)
+		}
+
+		if token.Lookup(name).IsKeyword() || types.Universe.Lookup(name) != nil {
+			return m
+		}
+		return fn(pkg.ImportPath, name, pkg.Name)
+	})
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsperandio/autofx/analyzer"
+	"github.com/jsperandio/autofx/analyzer/definition"
+)
+
+// storePackage declares a Store interface implemented by MemStore.
+func storePackage(name string) *definition.Package {
+	pkg := definition.NewPackage(name, name)
+	pkg.ImportPath = "example.com/app/" + name
+
+	ctor := definition.NewFunction("NewMemStore")
+	ctor.Returns = []definition.Param{{Type: "*MemStore"}}
+	s := definition.NewStruct("MemStore")
+	s.Constructors = []definition.Function{*ctor}
+	pkg.Structs["MemStore"] = s
+
+	i := definition.NewInterface("Store")
+	i.Implementations = []string{"MemStore"}
+	pkg.Interfaces["Store"] = i
+	return pkg
+}
+
+func TestAppUsesPackageOptions(t *testing.T) {
+	pkgs := []*definition.Package{storePackage("users"), storePackage("orders")}
+	root := Options{Bindings: map[string]string{"Store": "DiskStore"}}
+	own := Options{Bindings: map[string]string{"Store": "MemStore"}}
+
+	tests := []struct {
+		name    string
+		pkgOpts map[string]Options
+		err     string
+	}{
+		{
+			name:    "every package configured",
+			pkgOpts: map[string]Options{"example.com/app/users": own, "example.com/app/orders": own},
+		},
+		{
+			name:    "package sharing the root options",
+			pkgOpts: map[string]Options{"example.com/app/orders": own},
+			err:     "interface Store is bound to DiskStore",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAppGenerator(pkgs, t.TempDir(), root, tt.pkgOpts).Generate()
+			if tt.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+// copyDir copies the files of the src directory tree into dst.
+func copyDir(t *testing.T, src string, dst string) {
+	t.Helper()
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Join(dst, filepath.Dir(rel)), 0o755)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), content, 0o644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppBuilds(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	// The app testdata packages form the example.com/app module, resolving fx like autofx does.
+	dir := t.TempDir()
+	copyDir(t, filepath.Join("testdata", "app"), dir)
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := os.ReadFile(filepath.Join("..", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == "go.mod" {
+			_, requires, _ := strings.Cut(string(content), "\n")
+			content = []byte("module example.com/app\n" + requires)
+		}
+		err = os.WriteFile(filepath.Join(dir, name), content, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOWORK", "off")
+
+	pkgs, err := analyzer.NewInspector().InspectPackages(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAppGenerator(pkgs, filepath.Join(dir, "cmd", "app"), Options{}, nil)
+	err = a.GeneratePackages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := a.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := string(f.Content)
+	for _, want := range []string{"api.Module(),", "store.Module(),", "api.Run,", `errors.New("*log.Logger is not provided")`} {
+		if !strings.Contains(main, want) {
+			t.Errorf("%s not found in main.go:\n%s", want, main)
+		}
+	}
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go vet: %v\n%s\n%s", err, out, main)
+	}
+}
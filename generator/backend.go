@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Backend emits the wiring code of a dependency graph as a set of files.
+type Backend interface {
+	Generate(g *Graph) ([]*File, error)
+}
+
+// backends holds the constructors of the available backends, indexed by name.
+var backends = map[string]func(opts Options) Backend{
+	"fx":    newFxBackend,
+	"wire":  newWireBackend,
+	"plain": newPlainBackend,
+}
+
+// NewBackend returns the backend named in the options, defaulting to fx.
+func NewBackend(opts Options) (Backend, error) {
+	name := opts.Backend
+	if name == "" {
+		name = "fx"
+	}
+
+	newBackend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %s, valid backends: %s", name, strings.Join(Backends(), ", "))
+	}
+	return newBackend(opts), nil
+}
+
+// Backends returns the names of the available backends.
+func Backends() []string {
+	return sortedKeys(backends)
+}
+
+// constructorArgs resolves the arguments of a provider constructor, using the expression returned by
+// resolve for each parameter type. Variadic parameters are expanded.
+func constructorArgs(p *Provider, resolve func(typ string) string) string {
+	args := make([]string, len(p.Constructor.Params))
+	for i, prm := range p.Constructor.Params {
+		args[i] = resolve(prm.Type)
+		if strings.HasPrefix(prm.Type, "...") {
+			args[i] += "..."
+		}
+	}
+	return strings.Join(args, ", ")
+}
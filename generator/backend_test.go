@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// renderBackend renders the backends testdata package with the given backend, returning its single file.
+func renderBackend(t *testing.T, backend string) string {
+	t.Helper()
+	g, err := NewGraph(inspect(t, "backends"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBackend(Options{Backend: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := b.Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("%d files generated, want 1", len(files))
+	}
+
+	err = files[0].Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	declarations(t, files[0])
+	return string(files[0].Content)
+}
+
+func TestPlainBuildOrder(t *testing.T) {
+	build := renderBackend(t, "plain")
+
+	steps := []string{
+		"c.DB = NewDB(c.lifecycle)",
+		"c.Service = NewService(c.DB)",
+		"c.Handler, err = NewHandler(c.Service, p0)",
+	}
+	last := -1
+	for _, s := range steps {
+		i := strings.Index(build, s)
+		if i < 0 {
+			t.Fatalf("step %s not found:\n%s", s, build)
+		}
+		if i < last {
+			t.Errorf("step %s built before its dependencies:\n%s", s, build)
+		}
+		last = i
+	}
+}
+
+func TestPlainLifecycle(t *testing.T) {
+	build := renderBackend(t, "plain")
+
+	for _, want := range []string{
+		"func Build(p0 string) (*Container, error) {",
+		"type Container struct {\n\t*lifecycle\n",
+		"c := &Container{lifecycle: &lifecycle{}}",
+		"func (l *lifecycle) Append(h fx.Hook) {",
+		"func (l *lifecycle) Start(ctx context.Context) error {",
+		"func (l *lifecycle) Stop(ctx context.Context) error {",
+	} {
+		if !strings.Contains(build, want) {
+			t.Errorf("%s not found:\n%s", want, build)
+		}
+	}
+	if strings.Contains(build, "fx.Lifecycle") {
+		t.Errorf("fx.Lifecycle required by Build:\n%s", build)
+	}
+}
+
+func TestPlainUnsupportedFxType(t *testing.T) {
+	g, err := NewGraph(inspect(t, "shutdowner"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newPlainBackend(Options{}).Generate(g)
+	want := "the plain backend cannot provide fx.Shutdowner to NewStopper"
+	if err == nil || err.Error() != want {
+		t.Errorf("error %v, want %s", err, want)
+	}
+}
+
+func TestWireSet(t *testing.T) {
+	set := renderBackend(t, "wire")
+
+	want := `var ProviderSet = wire.NewSet(
+	NewDB,
+	wire.Bind(new(Store), new(*DB)),
+	NewHandler,
+	NewService,
+)`
+	if !strings.Contains(set, want) {
+		t.Errorf("provider set not found:\n%s", set)
+	}
+	if !strings.Contains(set, `"github.com/google/wire"`) {
+		t.Errorf("wire not imported:\n%s", set)
+	}
+}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+	"github.com/jsperandio/autofx/log"
+)
+
+const configFileName = "config.go"
+
+// configFile builds the file loading the configuration structs of the graph from the environment.
+// Fields without env tag, tagged env:"-" or of an unsupported type are left to their zero value.
+func configFile(g *Graph, opts Options) (*File, error) {
+	names := g.namer()
+	cd := tmpl.ConfigData{
+		PackageName: g.Package.Name,
+		Lookup:      names.unique("lookupEnv"),
+		Loaders:     make([]tmpl.LoaderData, len(g.Configs)),
+	}
+
+	imports := map[string]string{
+		"errors": "errors",
+		"os":     "os",
+	}
+	for i, c := range g.Configs {
+		ld := tmpl.LoaderData{
+			Name:   c.Loader,
+			Struct: c.Struct,
+		}
+
+		for _, f := range c.Fields {
+			env, ok := f.Lookup("env")
+			if !ok || env == "-" {
+				continue
+			}
+
+			fd, pkg, ok := configField(f, g.Package.Imports)
+			if !ok {
+				log.Warnf("config field %s.%s of type %s is not supported", c.Struct, f.Name, f.Type)
+				continue
+			}
+			fd.Env = env
+			fd.Default, fd.HasDefault = f.Lookup("default")
+			required, _ := f.Lookup("required")
+			fd.Required, _ = strconv.ParseBool(required)
+
+			if fd.Fallible {
+				imports["fmt"] = "fmt"
+			}
+			if pkg != "" {
+				imports[pkg] = pkg
+				if path, ok := g.Package.Imports[pkg]; ok {
+					imports[pkg] = path
+				}
+			}
+			ld.Fields = append(ld.Fields, fd)
+		}
+		cd.Loaders[i] = ld
+	}
+	cd.Imports = importList(imports)
+
+	t, err := loadTemplate(opts, "ConfigLoader", tmpl.ConfigLoader)
+	if err != nil {
+		return nil, err
+	}
+
+	f := NewFile(configFileName, g.Package.Path, nil)
+	err = t.Execute(f, cd)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// configField returns how a field of the given type is parsed from the variable value v, along with
+// the local name of the package parsing it, if any. The time package is resolved with the package imports.
+func configField(f definition.Field, imports map[string]string) (tmpl.ConfigFieldData, string, bool) {
+	fd := tmpl.ConfigFieldData{Name: f.Name}
+	pkg := "strconv"
+	switch f.Type {
+	case "string":
+		fd.Parse, pkg = "v", ""
+	case "[]string":
+		fd.Parse, pkg = `strings.Split(v, ",")`, "strings"
+	case "bool":
+		fd.Parse, fd.Fallible = "strconv.ParseBool(v)", true
+	case "int", "int8", "int16", "int32", "int64":
+		fd.Parse, fd.Fallible = fmt.Sprintf("strconv.ParseInt(v, 10, %s)", bitSize(f.Type, "int")), true
+		fd.Convert = convert(f.Type, "int64")
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		fd.Parse, fd.Fallible = fmt.Sprintf("strconv.ParseUint(v, 10, %s)", bitSize(f.Type, "uint")), true
+		fd.Convert = convert(f.Type, "uint64")
+	case "float32", "float64":
+		fd.Parse, fd.Fallible = fmt.Sprintf("strconv.ParseFloat(v, %s)", bitSize(f.Type, "float")), true
+		fd.Convert = convert(f.Type, "float64")
+	default:
+		q, name, ok := strings.Cut(f.Type, ".")
+		if !ok || name != "Duration" || imports[q] != "time" {
+			return fd, "", false
+		}
+		fd.Parse, fd.Fallible, pkg = q+".ParseDuration(v)", true, q
+	}
+	return fd, pkg, true
+}
+
+// bitSize returns the bit size argument of the strconv parser of a numeric type, 0 for int and uint.
+func bitSize(typ string, kind string) string {
+	size := strings.TrimPrefix(typ, kind)
+	if size == "" {
+		return "0"
+	}
+	return size
+}
+
+// convert returns the conversion of a parsed value to the given type, empty when already of that type.
+func convert(typ string, parsed string) string {
+	if typ == parsed {
+		return ""
+	}
+	return typ
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildLoader builds a program printing the ServerConfig loaded by the loader generated for the envconfig
+// testdata package, or the error it returns.
+func buildLoader(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	g, err := NewGraph(inspect(t, "envconfig"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := configFile(g, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := os.ReadFile(filepath.Join("testdata", "envconfig", "settings.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module loader\n\ngo 1.21\n",
+		"envconfig/settings.go": string(settings),
+		"envconfig/" + f.Name:   string(f.Content),
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"loader/envconfig"
+)
+
+func main() {
+	c, err := envconfig.LoadServerConfigFromEnv()
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%+v", *c)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bin := filepath.Join(dir, "loader")
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build loader: %v\n%s\n%s", err, out, f.Content)
+	}
+	return bin
+}
+
+func TestConfigLoader(t *testing.T) {
+	bin := buildLoader(t)
+
+	tests := []struct {
+		name string
+		env  []string
+		want string
+	}{
+		{
+			name: "defaults",
+			env:  []string{"SERVER_TOKEN=secret"},
+			want: "{Addr::8080 Port:8080 Workers:0 Ratio:0.5 Timeout:5s Debug:false Hosts:[] Token:secret Name:}",
+		},
+		{
+			name: "type conversion",
+			env: []string{
+				"SERVER_ADDR=:9090", "SERVER_PORT=9090", "SERVER_WORKERS=4", "SERVER_RATIO=0.25", "SERVER_TIMEOUT=1m",
+				"SERVER_DEBUG=true", "SERVER_HOSTS=a,b", "SERVER_TOKEN=secret", "SERVER_NAME=api",
+			},
+			want: "{Addr::9090 Port:9090 Workers:4 Ratio:0.25 Timeout:1m0s Debug:true Hosts:[a b] Token:secret Name:api}",
+		},
+		{
+			name: "set but empty with a default",
+			env:  []string{"SERVER_PORT=", "SERVER_TIMEOUT=", "SERVER_TOKEN=secret"},
+			want: "{Addr::8080 Port:8080 Workers:0 Ratio:0.5 Timeout:5s Debug:false Hosts:[] Token:secret Name:}",
+		},
+		{
+			name: "missing required",
+			env:  nil,
+			want: "SERVER_TOKEN is required",
+		},
+		{
+			name: "set but empty required",
+			env:  []string{"SERVER_TOKEN="},
+			want: "SERVER_TOKEN is required",
+		},
+		{
+			name: "invalid number",
+			env:  []string{"SERVER_PORT=eighty", "SERVER_WORKERS=300", "SERVER_TOKEN=secret"},
+			want: "SERVER_PORT: strconv.ParseInt: parsing \"eighty\": invalid syntax\n" +
+				"SERVER_WORKERS: strconv.ParseUint: parsing \"300\": value out of range",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(bin)
+			cmd.Env = tt.env
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(out)); got != tt.want {
+				t.Errorf("loaded\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+)
+
+const (
+	decoratorFileName = "decorator.go"
+	hookPackage       = "github.com/jsperandio/autofx/hook"
+)
+
+// Decorator is the generated decorator of an interface.
+type Decorator struct {
+	Interface string `json:"interface"`
+	// Name names the decorator type.
+	Name string `json:"name"`
+	// Func names the function wiring the decorator.
+	Func string `json:"func"`
+}
+
+// decorators returns the decorators of the interfaces marked with //autofx:decorate or listed in the options,
+// sorted by interface, named clear of the package identifiers.
+func decorators(pkg *definition.Package, opts Options, names *namer) ([]Decorator, error) {
+	for _, name := range opts.Decorators {
+		if _, ok := pkg.Interfaces[name]; !ok {
+			return nil, fmt.Errorf("decorated interface %s not found", name)
+		}
+	}
+
+	var decs []Decorator
+	for _, name := range sortedKeys(pkg.Interfaces) {
+		if !pkg.Interfaces[name].Decorated() && !slices.Contains(opts.Decorators, name) {
+			continue
+		}
+
+		fn := "Decorate" + upperCamel(name)
+		if !token.IsExported(name) {
+			fn = lowerCamel(fn)
+		}
+		decs = append(decs, Decorator{
+			Interface: name,
+			Name:      names.unique(name + "Decorator"),
+			Func:      names.unique(fn),
+		})
+	}
+	return decs, nil
+}
+
+// DecoratorOf returns the decorator of the given interface, if any.
+func (g *Graph) DecoratorOf(ifc string) *Decorator {
+	for i, d := range g.Decorators {
+		if d.Interface == ifc {
+			return &g.Decorators[i]
+		}
+	}
+	return nil
+}
+
+// RootDecorators returns the decorators of the public interfaces bound by the package module, to be applied
+// at the root of the application so that every consumer gets the decorated value. The decorators of private
+// interfaces are applied within the package module, the only place they can be consumed.
+func (g *Graph) RootDecorators() []Decorator {
+	var root []Decorator
+	for _, d := range g.Decorators {
+		if !g.PrivateInterface(d.Interface) && g.bound(d.Interface) {
+			root = append(root, d)
+		}
+	}
+	return root
+}
+
+// bound tells if the package module provides the given interface, bound to a provider or selected at runtime.
+func (g *Graph) bound(ifc string) bool {
+	for _, p := range g.Providers {
+		if slices.Contains(p.Interfaces, ifc) {
+			return true
+		}
+	}
+	return slices.ContainsFunc(g.Selectors, func(s Selector) bool { return s.Interface == ifc })
+}
+
+func (b *fxBackend) fillDecorators(g *Graph, f *File) error {
+	var params []definition.Param
+	decs := make([]tmpl.DecoratorData, len(g.Decorators))
+	for i, d := range g.Decorators {
+		ifc := g.Package.Interfaces[d.Interface]
+		decs[i] = tmpl.DecoratorData{
+			Interface: d.Interface,
+			Name:      d.Name,
+			FuncName:  d.Func,
+			Methods:   make([]tmpl.DecoratorMethodData, len(ifc.Methods)),
+		}
+		for j, m := range ifc.Methods {
+			decs[i].Methods[j] = tmpl.DecoratorMethodData{FakeMethodData: fakeMethod(m)}
+			if n := len(m.Returns); n > 0 && m.Returns[n-1].Type == "error" {
+				decs[i].Methods[j].Err = fmt.Sprintf("r%d", n-1)
+			}
+			params = append(params, m.Params...)
+			params = append(params, m.Returns...)
+		}
+	}
+
+	imports := g.Package.ImportsOf(params)
+	imports["hook"] = hookPackage
+
+	t, err := loadTemplate(b.opts, "GoFileInits", tmpl.GoFileInits)
+	if err != nil {
+		return err
+	}
+	err = t.Execute(f, tmpl.FileData{
+		PackageName: g.Package.Name,
+		Imports:     importList(imports),
+	})
+	if err != nil {
+		return err
+	}
+
+	t, err = loadTemplate(b.opts, "Decorator", tmpl.Decorator)
+	if err != nil {
+		return err
+	}
+	for _, dd := range decs {
+		err = t.Execute(f, dd)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
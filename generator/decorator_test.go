@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// decoratorsTest runs the module and decorators generated for the decorators testdata package with a hook.
+const decoratorsTest = `package decorators
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/fx"
+
+	"github.com/jsperandio/autofx/hook"
+)
+
+func TestDecorators(t *testing.T) {
+	var calls []string
+	h := hook.Funcs{AfterFunc: func(c *hook.Call) {
+		calls = append(calls, fmt.Sprintf("%s.%s%v %v", c.Interface, c.Method, c.Args, c.Err))
+	}}
+	app := fx.New(
+		Module(),
+		Decorators(),
+		fx.Supply(fx.Annotate(h, fx.As(new(hook.Hook)))),
+		fx.Invoke(func(s Store, svc *Service) {
+			s.Get("a")
+			s.Get("b")
+			s.Len()
+			s.Delete("a", "b")
+			s.Close()
+			svc.Cache.Lookup("k")
+		}),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Store.Get[a] <nil>",
+		"Store.Get[b] not found",
+		"Store.Len[] <nil>",
+		"Store.Delete[[a b]] <nil>",
+		"Store.Close[] <nil>",
+		"Cache.Lookup[k] <nil>",
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("calls %q, want %q", calls, want)
+	}
+}
+
+func TestDecoratorsWithoutHook(t *testing.T) {
+	app := fx.New(
+		Module(),
+		Decorators(),
+		fx.Invoke(func(s Store, svc *Service) {
+			if _, ok := s.(*StoreDecorator); ok {
+				t.Error("Store decorated without hook")
+			}
+			if _, ok := svc.Cache.(*CacheDecorator); ok {
+				t.Error("Cache decorated without hook")
+			}
+		}),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+`
+
+func TestDecorators(t *testing.T) {
+	g, err := NewGraph(inspect(t, "decorators"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Decorator{{Interface: "Store", Name: "StoreDecorator", Func: "DecorateStore"}}
+	if root := g.RootDecorators(); !reflect.DeepEqual(root, want) {
+		t.Errorf("root decorators %+v, want %+v", root, want)
+	}
+
+	b, err := NewBackend(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := b.Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join("testdata", "decorators")
+	overlay := map[string][]byte{filepath.Join(dir, "decorators_test.go"): []byte(decoratorsTest)}
+	for _, f := range files {
+		err = f.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+		overlay[filepath.Join(dir, f.Name)] = f.Content
+	}
+	if _, ok := overlay[filepath.Join(dir, decoratorFileName)]; !ok {
+		t.Fatalf("%s not generated", decoratorFileName)
+	}
+	runGo(t, overlay, "test", "-vet=off", "./"+dir)
+}
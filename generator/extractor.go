@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+)
+
+// Extraction is the result of extracting an interface from a struct method set.
+type Extraction struct {
+	Interface *definition.Interface
+	File      *File
+	// Switchable lists the consumer structs that only call methods of the extracted interface.
+	Switchable []string
+	// Blocked lists, for every other consumer, the called methods left out of the extracted interface.
+	Blocked map[string][]string
+}
+
+// Extractor extracts interfaces from the method sets of the package structs.
+type Extractor struct {
+	Package *definition.Package
+}
+
+// NewExtractor returns a new Extractor instance for the given package.
+func NewExtractor(pkg *definition.Package) *Extractor {
+	if pkg == nil {
+		return nil
+	}
+
+	return &Extractor{
+		Package: pkg,
+	}
+}
+
+// Extract builds an interface named ifaceName from the methods of structName.
+// When methods is empty, the methods called by the struct built by the consumer constructor are used,
+// and when there is no consumer either the whole method set is extracted.
+// The resulting file is not saved, leaving to the caller the choice of writing it.
+func (e *Extractor) Extract(structName, ifaceName string, methods []string, consumer string) (*Extraction, error) {
+	s, ok := e.Package.Structs[structName]
+	if !ok {
+		return nil, fmt.Errorf("struct not found %s", structName)
+	}
+
+	if e.declared(ifaceName) {
+		return nil, fmt.Errorf("identifier %s already declared in package %s", ifaceName, e.Package.Name)
+	}
+
+	if len(methods) == 0 && consumer != "" {
+		c, err := e.consumerStruct(consumer)
+		if err != nil {
+			return nil, err
+		}
+		methods = c.CallsOn(structName)
+		if len(methods) == 0 {
+			return nil, fmt.Errorf("consumer %s calls no method of %s", consumer, structName)
+		}
+	}
+
+	iface, err := s.ExtractInterface(ifaceName, methods)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := e.freeFile(ifaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := &Extraction{
+		Interface:  iface,
+		File:       f,
+		Switchable: make([]string, 0),
+		Blocked:    make(map[string][]string),
+	}
+
+	for _, c := range e.Package.Consumers(structName) {
+		var missing []string
+		for _, m := range c.CallsOn(structName) {
+			if !slices.ContainsFunc(iface.Methods, func(im definition.Method) bool { return im.Name == m }) {
+				missing = append(missing, m)
+			}
+		}
+
+		if len(missing) > 0 {
+			ext.Blocked[c.Name] = missing
+			continue
+		}
+		ext.Switchable = append(ext.Switchable, c.Name)
+	}
+
+	err = e.render(ext, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return ext, nil
+}
+
+// consumerStruct returns the struct built by the given constructor.
+func (e *Extractor) consumerStruct(constructor string) (*definition.Struct, error) {
+	for _, s := range e.Package.Structs {
+		for _, c := range s.Constructors {
+			if c.Name == constructor {
+				return s, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("consumer constructor not found %s", constructor)
+}
+
+// freeFile returns the file of the extracted interface, named after it like ext.go, or ext_iface.go when taken.
+// Existing files are never overwritten.
+func (e *Extractor) freeFile(ifaceName string) (*File, error) {
+	base := strings.ToLower(ifaceName)
+	for _, name := range []string{base + ".go", base + "_iface.go"} {
+		f := NewFile(name, e.Package.Path, nil)
+		exists, err := f.Exists()
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("files %s.go and %s_iface.go already exist in %s", base, base, e.Package.Path)
+}
+
+// declared checks if the name is already used by a package type or function.
+func (e *Extractor) declared(name string) bool {
+	_, isIface := e.Package.Interfaces[name]
+	_, isStruct := e.Package.Structs[name]
+	_, isFunc := e.Package.Functions[name]
+	return isIface || isStruct || isFunc
+}
+
+func (e *Extractor) render(ext *Extraction, s *definition.Struct) error {
+	t := template.Must(template.New("extractedInterface").Parse(tmpl.ExtractedInterface))
+
+	id := tmpl.InterfaceData{
+		PackageName: e.Package.Name,
+		Name:        ext.Interface.Name,
+		Source:      s.Name,
+		Methods:     make([]string, len(ext.Interface.Methods)),
+	}
+
+	var params []definition.Param
+	for i, m := range ext.Interface.Methods {
+		id.Methods[i] = m.Function.Signature()
+		params = append(params, m.Params...)
+		params = append(params, m.Returns...)
+	}
+	id.Imports = importList(e.Package.ImportsOf(params))
+
+	err := t.Execute(ext.File, id)
+	if err != nil {
+		return err
+	}
+
+	return ext.File.Format()
+}
@@ -1,8 +1,15 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+
+	"github.com/jsperandio/autofx/analyzer/parser"
+	"golang.org/x/tools/imports"
 )
 
 type File struct {
@@ -22,7 +29,33 @@ func NewFile(name string, path string, content []byte) *File {
 	}
 }
 
+// Exists tells if the file is already present on disk.
+func (f *File) Exists() (bool, error) {
+	_, err := os.Stat(filepath.Join(f.Path, f.Name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Overwritable tells if the file is either absent from disk or generated by autofx, so saving it loses no code.
+func (f *File) Overwritable() (bool, error) {
+	content, err := os.ReadFile(filepath.Join(f.Path, f.Name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return bytes.HasPrefix(content, []byte(parser.GeneratedHeader)), nil
+}
+
 func (f *File) Save() (*os.File, error) {
+	err := os.MkdirAll(f.Path, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
 	nf, err := os.Create(fmt.Sprintf("%s/%s", f.Path, f.Name))
 	if err != nil {
 		return nil, err
@@ -42,3 +75,19 @@ func (f *File) Write(p []byte) (n int, err error) {
 	f.Content = append(f.Content, p...)
 	return len(p), nil
 }
+
+// Format formats the file content as Go source code, grouping the standard library imports apart.
+func (f *File) Format() error {
+	src, err := imports.Process(f.Name, f.Content, &imports.Options{
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8,
+		FormatOnly: true,
+	})
+	if err != nil {
+		return fmt.Errorf("format %s: %w", f.Name, err)
+	}
+
+	f.Content = src
+	return nil
+}
@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+)
+
+// fxPackage is the import path of uber-go/fx, whose framework types fx provides by itself.
+const fxPackage = "go.uber.org/fx"
+
+// frameworkTypes are the types made available to every module by the application framework rather than
+// by a package, by canonical name. The fx ones are provided by fx itself, the root context and the loggers
+// by the application root.
+var frameworkTypes = []string{
+	fxPackage + ".Lifecycle",
+	fxPackage + ".Shutdowner",
+	fxPackage + ".DotGraph",
+	"context.Context",
+	"*go.uber.org/zap.Logger",
+	"*go.uber.org/zap.SugaredLogger",
+	"*log/slog.Logger",
+}
+
+// FrameworkTypes returns the built-in framework types followed by the ones added in the options.
+func FrameworkTypes(opts Options) []string {
+	return append(slices.Clone(frameworkTypes), opts.FrameworkTypes...)
+}
+
+// isFramework tells if a type declared in the given package is one of the framework types.
+func isFramework(typ string, pkg *definition.Package, framework []string) bool {
+	return slices.Contains(framework, canonicalType(typ, pkg))
+}
+
+// providedByFx tells if a framework type declared in the given package is provided by fx itself.
+func providedByFx(typ string, pkg *definition.Package) bool {
+	return strings.HasPrefix(strings.TrimLeft(canonicalType(typ, pkg), "*"), fxPackage+".")
+}
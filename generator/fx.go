@@ -0,0 +1,468 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+)
+
+const (
+	defaultFileName = "module.go"
+	testFileName    = "module_test.go"
+	fakeFileName    = "fake.go"
+
+	composePackage = "github.com/jsperandio/autofx/compose"
+)
+
+// fxBackend emits uber-go/fx modules, along with the optional assertions, test harness and fakes.
+type fxBackend struct {
+	opts Options
+}
+
+func newFxBackend(opts Options) Backend {
+	return &fxBackend{
+		opts: opts,
+	}
+}
+
+// Generate emits the files of the fx backend, their identifiers being named by a single namer so that
+// they never collide with each other nor with the package ones.
+func (b *fxBackend) Generate(g *Graph) ([]*File, error) {
+	names := g.namer()
+	module := NewFile(defaultFileName, g.Package.Path, nil)
+	err := b.fillModule(g, module, names)
+	if err != nil {
+		return nil, err
+	}
+	files := []*File{module}
+
+	if b.opts.TestHarness {
+		test := NewFile(testFileName, g.Package.Path, nil)
+		err = b.fillTestHarness(g, test, names)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, test)
+	}
+
+	if b.opts.Fakes {
+		fake := NewFile(fakeFileName, g.Package.Path, nil)
+		err = b.fillFakes(g, fake, names)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, fake)
+	}
+
+	if len(g.Decorators) > 0 {
+		dec := NewFile(decoratorFileName, g.Package.Path, nil)
+		err = b.fillDecorators(g, dec)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dec)
+	}
+
+	return files, nil
+}
+
+func (b *fxBackend) fillModule(g *Graph, f *File, names *namer) error {
+	t, err := loadTemplate(b.opts, "GoFileInits", tmpl.GoFileInits)
+	if err != nil {
+		return err
+	}
+
+	imports := g.Package.ImportsOf(append(slices.Clone(g.Requirements), g.Instantiated()...))
+	imports["fx"] = "go.uber.org/fx"
+	if b.opts.Registry {
+		imports["compose"] = composePackage
+	}
+	for _, r := range g.Recipes {
+		for local, path := range r.Imports {
+			if p, ok := g.Package.Imports[local]; ok && p != path {
+				return fmt.Errorf("recipe %s imports %s as %s, already importing %s", r.Name, path, local, p)
+			}
+			imports[local] = path
+		}
+	}
+	for _, s := range g.Selectors {
+		imports["fmt"] = "fmt"
+		if s.Env != "" {
+			imports["os"] = "os"
+		}
+	}
+	err = t.Execute(f, tmpl.FileData{
+		PackageName: g.Package.Name,
+		Imports:     importList(imports),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(g.Requirements) > 0 {
+		t, err = loadTemplate(b.opts, "RequirementsContract", tmpl.RequirementsContract)
+		if err != nil {
+			return err
+		}
+		err = t.Execute(f, contractData(g, names.unique("Requirements")))
+		if err != nil {
+			return err
+		}
+	}
+
+	groups := make(map[string]tmpl.GroupData, len(g.Recipes))
+	if len(g.Recipes) > 0 {
+		t, err = loadTemplate(b.opts, "GroupRecipe", tmpl.GroupRecipe)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range g.Recipes {
+		rd := tmpl.RecipeData{
+			GroupData: tmpl.GroupData{
+				Name: r.Name,
+				Type: names.unique(r.Type),
+				Tag:  fmt.Sprintf("`group:%q`", r.Name),
+			},
+			Interface: r.Interface,
+			Target:    r.Target,
+			Register:  r.Register,
+		}
+		if r.Consumer {
+			rd.ConsumerName = names.unique("Register" + upperCamel(r.Name))
+		}
+		groups[r.Name] = rd.GroupData
+
+		err = t.Execute(f, rd)
+		if err != nil {
+			return err
+		}
+	}
+
+	pd := tmpl.PackageData{
+		PackageName:  g.Package.Name,
+		ModuleName:   g.ModuleName,
+		OptionName:   names.unique("Option"),
+		Path:         g.Package.Path,
+		Modules:      make([]tmpl.ProviderData, 0),
+		Requirements: g.Requirements,
+	}
+	assertions := make([]tmpl.AssertionData, 0)
+
+	t, err = loadTemplate(b.opts, "ProviderModule", tmpl.ProviderModule)
+	if err != nil {
+		return err
+	}
+	for _, p := range g.Providers {
+		if p.Existing && len(p.Interfaces) == 0 {
+			continue
+		}
+
+		md := providerData(p, g.ModuleNames[p])
+		md.WithName = names.unique("With" + upperCamel(p.Name))
+		md.WithoutName = names.unique("Without" + upperCamel(p.Name))
+		for _, grp := range p.Groups {
+			md.Groups = append(md.Groups, groups[grp])
+		}
+		for _, ifc := range p.Interfaces {
+			if d := g.DecoratorOf(ifc); d != nil && g.PrivateInterface(ifc) {
+				md.Decorators = append(md.Decorators, d.Func)
+			}
+			if g.PrivateInterface(ifc) == md.Private {
+				md.Interfaces = append(md.Interfaces, ifc)
+			} else {
+				md.Forwarded = append(md.Forwarded, ifc)
+			}
+			assertions = append(assertions, tmpl.AssertionData{
+				Interface: ifc,
+				Value:     assertionValue(p.Type, g.Package.Structs[p.Name]),
+			})
+		}
+
+		err = t.Execute(f, md)
+		if err != nil {
+			return err
+		}
+		pd.Modules = append(pd.Modules, md)
+	}
+
+	if len(g.Selectors) > 0 {
+		t, err = loadTemplate(b.opts, "Selector", tmpl.Selector)
+		if err != nil {
+			return err
+		}
+	}
+	for _, s := range g.Selectors {
+		sd := selectorData(g, s, pd, names)
+		for _, impl := range s.Implementations {
+			assertions = append(assertions, tmpl.AssertionData{
+				Interface: s.Interface,
+				Value:     assertionValue(impl.Type, g.Package.Structs[impl.Provider]),
+			})
+		}
+		err = t.Execute(f, sd)
+		if err != nil {
+			return err
+		}
+		pd.Selectors = append(pd.Selectors, sd)
+	}
+
+	t, err = loadTemplate(b.opts, "PackageModule", tmpl.PackageModule)
+	if err != nil {
+		return err
+	}
+	err = t.Execute(f, pd)
+	if err != nil {
+		return err
+	}
+
+	if g.DecoratorsName != "" {
+		t, err = loadTemplate(b.opts, "RootDecorators", tmpl.RootDecorators)
+		if err != nil {
+			return err
+		}
+		dd := tmpl.RootDecoratorsData{
+			FuncName:   g.DecoratorsName,
+			ModuleName: g.ModuleName,
+		}
+		for _, d := range g.RootDecorators() {
+			dd.Funcs = append(dd.Funcs, d.Func)
+		}
+		err = t.Execute(f, dd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if b.opts.Registry {
+		t, err = loadTemplate(b.opts, "Registry", tmpl.Registry)
+		if err != nil {
+			return err
+		}
+		importPath := g.Package.ImportPath
+		if importPath == "" {
+			importPath = g.Package.Name
+		}
+		err = t.Execute(f, tmpl.RegistryData{
+			ImportPath:  importPath,
+			PackageName: g.Package.Name,
+			FuncName:    names.unique("Register"),
+			Modules:     pd.Modules,
+			Selectors:   pd.Selectors,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !b.opts.Assertions || len(assertions) == 0 {
+		return nil
+	}
+
+	t, err = loadTemplate(b.opts, "InterfaceAssertions", tmpl.InterfaceAssertions)
+	if err != nil {
+		return err
+	}
+	return t.Execute(f, assertions)
+}
+
+func (b *fxBackend) fillTestHarness(g *Graph, f *File, names *namer) error {
+	t, err := loadTemplate(b.opts, "TestHarness", tmpl.TestHarness)
+	if err != nil {
+		return err
+	}
+
+	external := append(g.External(), g.HandWired()...)
+	public := g.Public()
+	used := slices.Clone(external)
+	for _, typ := range public {
+		used = append(used, definition.Param{Type: typ})
+	}
+
+	imports := g.Package.ImportsOf(used)
+	imports["testing"] = "testing"
+	imports["fx"] = "go.uber.org/fx"
+	imports["fxtest"] = "go.uber.org/fx/fxtest"
+
+	td := tmpl.TestData{
+		PackageName:      g.Package.Name,
+		ModuleName:       g.ModuleName,
+		ValidationName:   names.unique("TestModuleValidation"),
+		ProvidesName:     names.unique("TestModuleProvides"),
+		RequirementsName: names.unique("testRequirements"),
+		Imports:          importList(imports),
+		Provided:         public,
+		Requirements:     make([]string, len(external)),
+	}
+	for i, r := range external {
+		td.Requirements[i] = r.Type
+	}
+
+	return t.Execute(f, td)
+}
+
+// fillFakes emits a fake of every package interface. The fakes of private interfaces have no WithFake option,
+// as a decorator given from outside the package module cannot reach them.
+func (b *fxBackend) fillFakes(g *Graph, f *File, names *namer) error {
+	provided := g.Provided()
+	ifcs := sortedKeys(g.Package.Interfaces)
+
+	fsd := tmpl.FakesData{
+		PackageName:    g.Package.Name,
+		ModuleName:     g.ModuleName,
+		TestModuleName: names.unique("TestModule"),
+		Fakes:          make([]tmpl.FakeData, len(ifcs)),
+	}
+
+	var params []definition.Param
+	for i, n := range ifcs {
+		ifc := g.Package.Interfaces[n]
+		fsd.Fakes[i] = tmpl.FakeData{
+			Interface: ifc.Type(),
+			Name:      names.unique("Fake" + upperCamel(ifc.Type())),
+			Provided:  slices.Contains(provided, ifc.Type()),
+			Methods:   make([]tmpl.FakeMethodData, len(ifc.Methods)),
+		}
+		if !g.PrivateInterface(ifc.Type()) {
+			fsd.Fakes[i].WithName = names.unique("WithFake" + upperCamel(ifc.Type()))
+		}
+		for j, m := range ifc.Methods {
+			fsd.Fakes[i].Methods[j] = fakeMethod(m)
+			params = append(params, m.Params...)
+			params = append(params, m.Returns...)
+		}
+	}
+
+	imports := g.Package.ImportsOf(params)
+	if len(ifcs) > 0 {
+		imports["sync"] = "sync"
+	}
+	imports["fx"] = "go.uber.org/fx"
+	fsd.Imports = importList(imports)
+
+	t, err := loadTemplate(b.opts, "FakeFileInits", tmpl.FakeFileInits)
+	if err != nil {
+		return err
+	}
+	err = t.Execute(f, fsd)
+	if err != nil {
+		return err
+	}
+
+	t, err = loadTemplate(b.opts, "Fake", tmpl.Fake)
+	if err != nil {
+		return err
+	}
+	for _, fd := range fsd.Fakes {
+		err = t.Execute(f, fd)
+		if err != nil {
+			return err
+		}
+	}
+
+	t, err = loadTemplate(b.opts, "TestModule", tmpl.TestModule)
+	if err != nil {
+		return err
+	}
+	return t.Execute(f, fsd)
+}
+
+// providerData builds the template data of a provider module, leaving the interfaces to the caller.
+// The type of a hand-wired provider is taken as public, its module only binding the interfaces.
+func providerData(p *Provider, moduleName string) tmpl.ProviderData {
+	return tmpl.ProviderData{
+		ModuleData: tmpl.ModuleData{
+			ConstructorName: p.Constructor.Name,
+		},
+		ModuleName:  moduleName,
+		Struct:      p.Name,
+		Type:        p.Type,
+		Constructor: p.Constructor,
+		Fallible:    p.Fallible(),
+		Private:     p.Private && !p.Existing,
+		Existing:    p.Existing,
+	}
+}
+
+// selectorData builds the template data of a selector, named clear of the names given before.
+func selectorData(g *Graph, s Selector, pd tmpl.PackageData, names *namer) tmpl.SelectorData {
+	sd := tmpl.SelectorData{
+		Interface:  s.Interface,
+		ModuleName: pd.ModuleName,
+		OptionName: pd.OptionName,
+		OptionFunc: names.unique("Select" + upperCamel(s.Interface)),
+		FuncName:   names.unique("select" + upperCamel(s.Interface)),
+		Env:        s.Env,
+		Keys:       s.Keys(),
+		Private:    g.PrivateInterface(s.Interface),
+	}
+	for _, c := range g.Configs {
+		if c.Struct == s.Config {
+			sd.Loader = c.Loader
+			sd.Field = s.Field
+			sd.ConfigFunc = names.unique(sd.FuncName + "From" + upperCamel(c.Struct))
+		}
+	}
+	if d := g.DecoratorOf(s.Interface); d != nil && sd.Private {
+		sd.Decorator = d.Func
+	}
+	for _, impl := range s.Implementations {
+		if impl.Provider == s.Default {
+			sd.Default = impl.Key
+		}
+		sd.Implementations = append(sd.Implementations, tmpl.SelectionData{
+			Key:  impl.Key,
+			Type: impl.Type,
+		})
+	}
+	return sd
+}
+
+// fakeMethod builds the fake template data of an interface method, naming its parameters p0..pN and results r0..rN.
+func fakeMethod(m definition.Method) tmpl.FakeMethodData {
+	var params, args, record, paramTypes, results, resultTypes []string
+	for i, p := range m.Params {
+		name := fmt.Sprintf("p%d", i)
+		params = append(params, fmt.Sprintf("%s %s", name, p.Type))
+		paramTypes = append(paramTypes, p.Type)
+		record = append(record, name)
+		if strings.HasPrefix(p.Type, "...") {
+			name += "..."
+		}
+		args = append(args, name)
+	}
+
+	for i, r := range m.Returns {
+		results = append(results, fmt.Sprintf("r%d %s", i, r.Type))
+		resultTypes = append(resultTypes, r.Type)
+	}
+
+	fmd := tmpl.FakeMethodData{
+		Name:     m.Name,
+		Params:   strings.Join(params, ", "),
+		Args:     strings.Join(args, ", "),
+		Record:   strings.Join(record, ", "),
+		FuncType: fmt.Sprintf("func(%s)", strings.Join(paramTypes, ", ")),
+	}
+	if len(results) > 0 {
+		fmd.Results = fmt.Sprintf("(%s)", strings.Join(results, ", "))
+		fmd.FuncType += fmt.Sprintf(" (%s)", strings.Join(resultTypes, ", "))
+	}
+
+	return fmd
+}
+
+// assertionValue returns the zero value expression of the type built by a provider constructor,
+// given the definition of that type if known.
+func assertionValue(typ string, s *definition.Struct) string {
+	if strings.HasPrefix(typ, "*") {
+		return fmt.Sprintf("(%s)(nil)", typ)
+	}
+	if s != nil && s.Underlying != "" {
+		return fmt.Sprintf("*new(%s)", typ)
+	}
+	return fmt.Sprintf("%s{}", typ)
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// moduleFunc returns the source of the generated module function of the given name.
+func moduleFunc(t *testing.T, src string, name string) string {
+	t.Helper()
+	start := strings.Index(src, "func "+name+"() fx.Option {")
+	if start < 0 {
+		t.Fatalf("module %s not found:\n%s", name, src)
+	}
+	end := strings.Index(src[start:], "\n}\n")
+	return src[start : start+end]
+}
+
+func TestFxPrivateProvider(t *testing.T) {
+	g, err := NewGraph(inspect(t, "private"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBackend(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := b.Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = files[0].Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(files[0].Content)
+
+	if !strings.Contains(src, "return fx.Module(\n\t\t\"private\",") {
+		t.Errorf("package module not named after the package:\n%s", src)
+	}
+	var module string
+	for p, name := range g.ModuleNames {
+		if p.Name == "DiskStore" {
+			module = name
+		}
+	}
+	provides := strings.Split(moduleFunc(t, src, module), "fx.Provide(")[1:]
+	for _, p := range provides {
+		private := strings.Contains(p, "fx.Private")
+		if strings.Contains(p, "NewDiskStore") && !private {
+			t.Errorf("private DiskStore provided outside of the package module:\n%s", p)
+		}
+		if strings.Contains(p, "new(Store)") && private {
+			t.Errorf("Store implemented by private DiskStore not provided outside of the package module:\n%s", p)
+		}
+	}
+	if len(provides) != 2 {
+		t.Errorf("%d provides of DiskStore, want 2:\n%s", len(provides), src)
+	}
+	if public := g.Public(); !slices.Equal(public, []string{"Store"}) {
+		t.Errorf("public types %v, want [Store]", public)
+	}
+}
+
+// backendsOptionsTest runs the module generated for the backends testdata package with its options.
+const backendsOptionsTest = `package backends
+
+import (
+	"testing"
+
+	"go.uber.org/fx"
+)
+
+func TestOptions(t *testing.T) {
+	run := func(opts ...fx.Option) error {
+		return fx.New(append(opts, fx.NopLogger)...).Err()
+	}
+
+	err := run(Module(), fx.Supply("name"), fx.Invoke(func(*Handler) {}))
+	if err != nil {
+		t.Errorf("default providers: %v", err)
+	}
+
+	err = run(Module(WithoutHandler()), fx.Invoke(func(*Service) {}))
+	if err != nil {
+		t.Errorf("dropped Handler still requiring its name: %v", err)
+	}
+
+	err = run(Module(WithoutDB()), fx.Invoke(func(Store) {}))
+	if err == nil {
+		t.Error("dropped DB still providing Store")
+	}
+
+	db := &DB{}
+	replacement := fx.Provide(fx.Annotate(func() *DB { return db }, fx.As(fx.Self()), fx.As(new(Store))))
+	err = run(Module(WithDB(replacement)), fx.Invoke(func(s Store) {
+		if s != db {
+			t.Error("Store not provided by the replacement of DB")
+		}
+	}))
+	if err != nil {
+		t.Errorf("replaced DB: %v", err)
+	}
+}
+`
+
+func TestFxModuleOptions(t *testing.T) {
+	g, err := NewGraph(inspect(t, "backends"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBackend(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := b.Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = files[0].Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decls := declarations(t, files[0])
+	for _, name := range []string{"Option", "WithoutDB", "WithDB", "WithoutService", "WithService", "WithoutHandler", "WithHandler"} {
+		if decls[name] != 1 {
+			t.Errorf("%s declared %d times, want 1", name, decls[name])
+		}
+	}
+
+	dir := filepath.Join("testdata", "backends")
+	runGo(t, map[string][]byte{
+		filepath.Join(dir, files[0].Name):     files[0].Content,
+		filepath.Join(dir, "options_test.go"): []byte(backendsOptionsTest),
+	}, "test", "-vet=off", "./"+dir)
+}
@@ -2,156 +2,186 @@ package generator
 
 import (
 	"fmt"
-	"text/template"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/jsperandio/autofx/analyzer/definition"
 	tmpl "github.com/jsperandio/autofx/generator/template"
+	"github.com/jsperandio/autofx/log"
 )
 
-const defaultFileName = "module.go"
+// Options toggles the optional outputs of the Generator.
+type Options struct {
+	// Backend names the backend emitting the wiring code, fx when empty.
+	Backend string `json:"backend,omitempty"`
+	// Assertions emits a compile-time assertion for every fx.As binding.
+	Assertions bool `json:"assertions,omitempty"`
+	// TestHarness emits a module_test.go checking that every provided type can be populated.
+	TestHarness bool `json:"testHarness,omitempty"`
+	// Fakes emits a fake.go with a fake of every interface and a TestModule using them.
+	Fakes bool `json:"fakes,omitempty"`
+	// Manifest emits a requirements.json listing the types the package module requires from outside.
+	Manifest bool `json:"manifest,omitempty"`
+	// Registry emits a Register function adding the providers and selectors of the package module to a
+	// compose.Registry, with the fx backend.
+	Registry bool `json:"registry,omitempty"`
+	// TemplateDir holds <Name>.tmpl files overriding the backend templates of the same name.
+	TemplateDir string `json:"templateDir,omitempty"`
+	// Templates maps template names to files overriding them, taking precedence over TemplateDir.
+	Templates map[string]string `json:"templates,omitempty"`
+	// FrameworkTypes adds to the built-in framework types, qualified by import path like *go.uber.org/zap.Logger,
+	// the types left to the application framework rather than required from the parent application.
+	FrameworkTypes []string `json:"frameworkTypes,omitempty"`
+	// Bindings maps interfaces to the struct bound to them, when several implement them.
+	Bindings map[string]string `json:"bindings,omitempty"`
+	// Selectors maps the interfaces provided by an implementation selected at runtime to the source of the
+	// selection key, either an env variable or the string field of a config struct like Settings.StoreKind,
+	// besides the ones marked with //autofx:select, with the fx backend.
+	Selectors map[string]string `json:"selectors,omitempty"`
+	// Constructors maps structs to the constructor providing them, when several return them.
+	Constructors map[string]string `json:"constructors,omitempty"`
+	// ConstructorRules orders the rules selecting the constructor of the other structs among directive, marker
+	// and fewestParams, all of them by default. Leaving a rule out disables it.
+	ConstructorRules []string `json:"constructorRules,omitempty"`
+	// Decorators lists the interfaces wrapped by a generated decorator calling a hook.Hook, besides the ones
+	// marked with //autofx:decorate, with the fx backend.
+	Decorators []string `json:"decorators,omitempty"`
+	// Recipes annotate into value groups the providers implementing their interface, with the fx backend.
+	Recipes []Recipe `json:"recipes,omitempty"`
+	// Naming configures the names of the generated module functions.
+	Naming Naming `json:"naming,omitempty"`
+	// Plugins are external generators run after the backend, in order.
+	Plugins []Plugin `json:"plugins,omitempty"`
+}
 
 type Generator struct {
 	Package *definition.Package
-
-	dependencies  map[*definition.Struct][]string
-	resultModules []string
-	resultFile    *File
+	Options Options
 }
 
-func NewGenerator(pkg *definition.Package) *Generator {
+func NewGenerator(pkg *definition.Package, opts Options) *Generator {
 	if pkg == nil {
 		return nil
 	}
 
 	return &Generator{
-		Package:       pkg,
-		dependencies:  make(map[*definition.Struct][]string),
-		resultFile:    NewFile(defaultFileName, pkg.Path, nil),
-		resultModules: []string{},
+		Package: pkg,
+		Options: opts,
 	}
 }
 
 func (g *Generator) Generate() error {
-	g.buildDependencyMap()
-
-	err := g.initFileContent()
+	err := checkTemplates(g.Options)
 	if err != nil {
 		return err
 	}
 
-	err = g.fillSimpleTemplates()
+	graph, err := NewGraph(g.Package, g.Options)
 	if err != nil {
 		return err
 	}
 
-	err = g.fillInterfaceTemplates()
+	backend, err := NewBackend(g.Options)
 	if err != nil {
 		return err
 	}
 
-	err = g.fillPackageModule()
+	files, err := backend.Generate(graph)
 	if err != nil {
 		return err
 	}
 
-	_, err = g.resultFile.Save()
-	if err != nil {
-		return err
+	if g.Options.Manifest {
+		mf, err := manifestFile(graph)
+		if err != nil {
+			return err
+		}
+		files = append(files, mf)
 	}
 
-	return nil
-}
-
-func (g *Generator) buildDependencyMap() {
-	for _, v := range g.Package.Structs {
-		if len(v.Constructor.Params) == 0 {
-			g.dependencies[v] = nil
-			continue
-		}
-		deps := make([]string, len(v.Constructor.Params))
-		for i, p := range v.Constructor.Params {
-			deps[i] = p.BaseType()
+	if len(graph.Configs) > 0 {
+		cf, err := configFile(graph, g.Options)
+		if err != nil {
+			return err
 		}
-		g.dependencies[v] = deps
+		files = append(files, cf)
 	}
-}
 
-func (g *Generator) initFileContent() error {
-	t := template.Must(template.New("init").Parse(tmpl.GoFileInits))
-	err := t.Execute(g.resultFile, g.Package.Name)
-	if err != nil {
-		return err
+	for _, p := range g.Options.Plugins {
+		pf, err := p.Run([]PluginPackage{{Definition: g.Package, Graph: graph}})
+		if err != nil {
+			return err
+		}
+		files = append(files, pf...)
 	}
 
-	return nil
-}
-
-func (g *Generator) fillSimpleTemplates() error {
-	t := template.Must(template.New("simpleModule").Parse(tmpl.SimpleModule))
-
-	for dep, needs := range g.dependencies {
-		if needs != nil || len(needs) > 0 {
+	for _, f := range files {
+		if filepath.Ext(f.Name) != ".go" {
+			_, err = f.Save()
+			if err != nil {
+				return err
+			}
 			continue
 		}
 
-		md := tmpl.ModuleData{
-			PackageName:     g.Package.Name,
-			ConstructorName: dep.Constructor.Name,
-			ImplementType:   dep.Name,
+		err = f.Format()
+		if err != nil {
+			return err
 		}
 
-		err := t.Execute(g.resultFile, md)
+		err = SaveGenerated(f)
 		if err != nil {
 			return err
 		}
-
-		g.resultModules = append(g.resultModules, dep.Name)
 	}
 
 	return nil
 }
 
-func (g *Generator) fillInterfaceTemplates() error {
-	t := template.Must(template.New("interfaceModule").Parse(tmpl.InterfaceModule))
-
-	for _, ifc := range g.Package.Interfaces {
-		md := tmpl.ModuleData{
-			ImplementType: ifc.Type(),
-		}
+// SaveGenerated saves a generated Go file, unless it would overwrite a hand-written one. The file is then
+// saved as <name>_autofx.go, and an error is returned if that name is hand-written too.
+func SaveGenerated(f *File) error {
+	ok, err := f.Overwritable()
+	if err != nil {
+		return err
+	}
 
-		for _, impl := range ifc.Implementations {
-			s, ok := g.Package.Structs[impl]
-			if !ok {
-				return fmt.Errorf("struct not found %s", impl)
-			}
-			md.ConstructorName = s.Constructor.Name
+	if !ok {
+		name := f.Name
+		f.Name = strings.TrimSuffix(name, ".go") + "_autofx.go"
+		if strings.HasSuffix(name, "_test.go") {
+			f.Name = strings.TrimSuffix(name, "_test.go") + "_autofx_test.go"
 		}
 
-		err := t.Execute(g.resultFile, md)
+		ok, err = f.Overwritable()
 		if err != nil {
 			return err
 		}
-
-		g.resultModules = append(g.resultModules, ifc.Type())
+		if !ok {
+			return fmt.Errorf("%s and %s in %s are not generated by autofx, not overwritten", name, f.Name, f.Path)
+		}
+		log.Warnf("%s in %s is not generated by autofx, generated %s instead", name, f.Path, f.Name)
 	}
 
-	return nil
+	_, err = f.Save()
+	return err
 }
 
-func (g *Generator) fillPackageModule() error {
-	t := template.Must(template.New("packageModule").Parse(tmpl.PackageModule))
-
-	mdL := make([]tmpl.ModuleData, len(g.resultModules))
-	for i, m := range g.resultModules {
-		mdL[i] = tmpl.ModuleData{
-			ImplementType: m,
+// importList converts imports indexed by local name into a list sorted by path,
+// aliasing the ones whose name differs from the last path element.
+func importList(imports map[string]string) []tmpl.Import {
+	list := make([]tmpl.Import, 0, len(imports))
+	for name, path := range imports {
+		imp := tmpl.Import{Path: path}
+		if name != path[strings.LastIndex(path, "/")+1:] {
+			imp.Alias = name
 		}
+		list = append(list, imp)
 	}
 
-	err := t.Execute(g.resultFile, mdL)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	slices.SortFunc(list, func(a, b tmpl.Import) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return list
 }
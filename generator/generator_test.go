@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsperandio/autofx/analyzer/parser"
+)
+
+func TestSaveGenerated(t *testing.T) {
+	generated := parser.GeneratedHeader + "\n\npackage p\n"
+	handWritten := "package p\n\nfunc Module() {}\n"
+
+	tests := []struct {
+		name  string
+		file  string
+		disk  map[string]string
+		saved string
+		err   string
+	}{
+		{name: "absent", file: "module.go", saved: "module.go"},
+		{name: "generated", file: "module.go", disk: map[string]string{"module.go": generated}, saved: "module.go"},
+		{name: "hand-written", file: "module.go", disk: map[string]string{"module.go": handWritten}, saved: "module_autofx.go"},
+		{name: "hand-written test", file: "module_test.go", disk: map[string]string{"module_test.go": handWritten}, saved: "module_autofx_test.go"},
+		{
+			name: "hand-written under both names",
+			file: "module.go",
+			disk: map[string]string{"module.go": handWritten, "module_autofx.go": handWritten},
+			err:  "module.go and module_autofx.go in",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.disk {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			content := generated + "\nfunc Module() {}\n"
+			err := SaveGenerated(NewFile(tt.file, dir, []byte(content)))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(filepath.Join(dir, tt.saved))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != content {
+				t.Errorf("%s is %q, want %q", tt.saved, got, content)
+			}
+			for name, want := range tt.disk {
+				if name == tt.saved {
+					continue
+				}
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("hand-written %s overwritten with %q", name, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,453 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/log"
+)
+
+// Graph is the dependency graph of an analyzed package, as consumed by the backends.
+type Graph struct {
+	Package *definition.Package `json:"-"`
+	// ModuleName names the function composing the package modules.
+	ModuleName string `json:"moduleName"`
+	// ModuleNames names the module function of each provider, encoded by provider name.
+	ModuleNames map[*Provider]string `json:"-"`
+	Providers   []*Provider          `json:"providers"`
+	Configs     []Config             `json:"configs,omitempty"`
+	// Recipes holds the recipes grouping some of the providers.
+	Recipes []Recipe `json:"recipes,omitempty"`
+	// Decorators holds the interfaces to decorate.
+	Decorators []Decorator `json:"decorators,omitempty"`
+	// DecoratorsName names the function applying the RootDecorators, empty without any.
+	DecoratorsName string `json:"decoratorsName,omitempty"`
+	// Selectors holds the interfaces whose implementation is selected at runtime.
+	Selectors []Selector `json:"selectors,omitempty"`
+	// Requirements holds the constructor parameters no provider of the package satisfies.
+	Requirements []definition.Param `json:"requirements,omitempty"`
+	// Framework holds the requirements left to the application framework, as listed by FrameworkTypes.
+	Framework []definition.Param `json:"framework,omitempty"`
+}
+
+// Config is a configuration struct without constructor, provided by a generated Loader reading its env tagged fields.
+type Config struct {
+	Struct string             `json:"struct"`
+	Loader string             `json:"loader"`
+	Fields []definition.Field `json:"fields,omitempty"`
+}
+
+// Provider is a package constructor along with the types it provides: the constructor result as its own type
+// and every bound interface.
+type Provider struct {
+	Name        string              `json:"name"`
+	Type        string              `json:"type"`
+	Constructor definition.Function `json:"constructor"`
+	// Private tells if the constructor result stays internal to the package module.
+	Private bool `json:"private,omitempty"`
+	// TypeArgs holds the type arguments instantiating the constructor of a generic struct.
+	TypeArgs   []string `json:"typeArgs,omitempty"`
+	Interfaces []string `json:"interfaces,omitempty"`
+	// Groups holds the value groups of the recipes it implements.
+	Groups []string `json:"groups,omitempty"`
+	// Existing tells if the constructor is already provided by hand-written fx wiring.
+	Existing bool `json:"existing,omitempty"`
+}
+
+// defaultModuleName names the function composing the package modules, unless the package already declares it.
+const defaultModuleName = "Module"
+
+// NewGraph builds the dependency graph of a package, every struct with a constructor becoming a provider.
+// Interfaces are bound to the implementation set in the options bindings, or else to the last one by name,
+// unless selected at runtime. Module functions are named following the options naming, clear of the package
+// identifiers.
+func NewGraph(pkg *definition.Package, opts Options) (*Graph, error) {
+	g := &Graph{
+		Package:      pkg,
+		ModuleName:   defaultModuleName,
+		ModuleNames:  make(map[*Provider]string),
+		Providers:    make([]*Provider, 0),
+		Requirements: make([]definition.Param, 0),
+	}
+
+	err := definition.CheckConstructorRules(opts.ConstructorRules)
+	if err != nil {
+		return nil, err
+	}
+
+	names := newNamer(opts.Naming, pkg.Identifiers)
+	if slices.Contains(pkg.Identifiers, defaultModuleName) {
+		g.ModuleName = "Generated" + defaultModuleName
+		log.Warnf("package %s already declares %s, generated module named %s", pkg.Name, defaultModuleName, g.ModuleName)
+	}
+	g.ModuleName = names.unique(g.ModuleName)
+
+	byStruct := make(map[string]*Provider)
+	for _, name := range sortedKeys(pkg.Structs) {
+		s := pkg.Structs[name]
+		ctor, err := s.SelectConstructor(opts.Constructors[s.Name], opts.ConstructorRules)
+		if err != nil {
+			return nil, err
+		}
+		if s.Config() && ctor.Name != "" {
+			log.Warnf("config struct %s is built by %s, not loaded from the environment", s.Name, ctor.Name)
+		} else if s.Config() {
+			c := Config{
+				Struct: s.Name,
+				Loader: names.unique("Load" + upperCamel(s.Name) + "FromEnv"),
+				Fields: s.Fields,
+			}
+			g.Configs = append(g.Configs, c)
+
+			ctor = *definition.NewFunction(c.Loader)
+			ctor.Returns = []definition.Param{{Type: "*" + s.Name}, {Type: "error"}}
+		}
+		if ctor.Name == "" || len(ctor.Returns) == 0 {
+			continue
+		}
+
+		if len(s.TypeParams) > 0 {
+			g.Providers = append(g.Providers, instantiations(s, ctor, pkg, names)...)
+			continue
+		}
+
+		p := &Provider{
+			Name:        s.Name,
+			Type:        ctor.Returns[0].Type,
+			Constructor: ctor,
+			Private:     s.Private(),
+			Interfaces:  make([]string, 0),
+			Existing:    pkg.Wiring.Constructors[ctor.Name] > 0,
+		}
+		byStruct[s.Name] = p
+		g.Providers = append(g.Providers, p)
+	}
+
+	for _, name := range sortedKeys(pkg.Interfaces) {
+		ifc := pkg.Interfaces[name]
+		if slices.Contains(pkg.Wiring.Provided, ifc.Type()) {
+			continue
+		}
+
+		impls := slices.Clone(ifc.Implementations)
+		slices.Sort(impls)
+
+		candidates := make([]string, 0, len(impls))
+		for _, impl := range impls {
+			if _, ok := pkg.Structs[impl]; !ok {
+				return nil, fmt.Errorf("struct not found %s", impl)
+			}
+			if _, ok := byStruct[impl]; ok {
+				candidates = append(candidates, impl)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		bound := candidates[len(candidates)-1]
+		if impl, ok := opts.Bindings[ifc.Type()]; ok {
+			if !slices.Contains(candidates, impl) {
+				return nil, fmt.Errorf("interface %s is bound to %s, which does not implement it or has no constructor", ifc.Type(), impl)
+			}
+			bound = impl
+		}
+
+		if source, ok := selected(ifc, opts); ok {
+			providers := make([]*Provider, len(candidates))
+			for i, c := range candidates {
+				providers[i] = byStruct[c]
+			}
+			sel, err := newSelector(ifc, source, bound, providers, g.Configs, pkg)
+			if err != nil {
+				return nil, err
+			}
+			g.Selectors = append(g.Selectors, sel)
+			continue
+		}
+
+		if _, ok := opts.Bindings[ifc.Type()]; !ok && len(candidates) > 1 {
+			log.Warnf("interface %s is implemented by %s, bound to %s", ifc.Type(), strings.Join(candidates, ", "), bound)
+		}
+
+		byStruct[bound].Interfaces = append(byStruct[bound].Interfaces, ifc.Type())
+	}
+
+	for _, p := range g.Providers {
+		g.ModuleNames[p] = names.module(p.Name)
+	}
+
+	g.Recipes, err = recipes(g, opts)
+	if err != nil {
+		return nil, err
+	}
+	g.Decorators, err = decorators(pkg, opts, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.RootDecorators()) > 0 {
+		g.DecoratorsName = names.unique("Decorators")
+	}
+
+	g.Requirements, g.Framework = g.requirements(FrameworkTypes(opts))
+	return g, nil
+}
+
+// instantiations returns a provider for each instantiation of a generic struct configured with //autofx:instantiate,
+// named after the struct and its type arguments like RepoUser, clear of the package identifiers.
+// Generic structs are never bound to interfaces.
+func instantiations(s *definition.Struct, generic definition.Function, pkg *definition.Package, names *namer) []*Provider {
+	insts := s.Instantiations()
+	if len(insts) == 0 {
+		log.Warnf("generic struct %s has no //autofx:instantiate directive, not provided", s.Name)
+		return nil
+	}
+
+	providers := make([]*Provider, 0, len(insts))
+	for _, args := range insts {
+		ctor, err := generic.Instantiate(args)
+		if err != nil {
+			log.Warnf("generic struct %s not instantiated with %s: %s", s.Name, strings.Join(args, ", "), err)
+			continue
+		}
+
+		name := s.Name
+		for _, a := range args {
+			ids := typeIdentRegexp.FindAllStringSubmatch(a, -1)
+			for _, id := range ids {
+				name += upperCamel(id[2])
+			}
+		}
+
+		providers = append(providers, &Provider{
+			Name:        names.unique(name),
+			Type:        ctor.Returns[0].Type,
+			Constructor: ctor,
+			Private:     s.Private(),
+			TypeArgs:    args,
+			Interfaces:  make([]string, 0),
+			Existing:    pkg.Wiring.Constructors[ctor.Name] > 0,
+		})
+	}
+	return providers
+}
+
+// MarshalJSON encodes the graph with the module names keyed by provider name.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	type graph Graph
+	moduleNames := make(map[string]string, len(g.ModuleNames))
+	for p, name := range g.ModuleNames {
+		moduleNames[p.Name] = name
+	}
+	return json.Marshal(struct {
+		*graph
+		ModuleNames map[string]string `json:"moduleNames"`
+	}{(*graph)(g), moduleNames})
+}
+
+// Provided returns every type the graph providers and the hand-written wiring make available.
+func (g *Graph) Provided() []string {
+	provided := slices.Clone(g.Package.Wiring.Provided)
+	for _, p := range g.Providers {
+		provided = append(provided, p.Type)
+		provided = append(provided, p.Interfaces...)
+	}
+	for _, s := range g.Selectors {
+		provided = append(provided, s.Interface)
+	}
+	return provided
+}
+
+// Public returns the types provided by the generated package module that are visible outside of it, once each.
+// Hand-wired types are left out, unlike the interfaces the module binds to them.
+func (g *Graph) Public() []string {
+	public := make([]string, 0)
+	add := func(typ string) {
+		if !slices.Contains(public, typ) {
+			public = append(public, typ)
+		}
+	}
+
+	for _, p := range g.Providers {
+		if !p.Private && !p.Existing {
+			add(p.Type)
+		}
+		for _, ifc := range p.Interfaces {
+			if !g.PrivateInterface(ifc) {
+				add(ifc)
+			}
+		}
+	}
+	for _, s := range g.Selectors {
+		if !g.PrivateInterface(s.Interface) {
+			add(s.Interface)
+		}
+	}
+	return public
+}
+
+// HandWired returns the types provided by hand-written wiring that the generated package module consumes,
+// either as constructor parameters or as the implementation of a bound interface.
+func (g *Graph) HandWired() []definition.Param {
+	wired := slices.Clone(g.Package.Wiring.Provided)
+	for _, p := range g.Providers {
+		if p.Existing {
+			wired = append(wired, p.Type)
+		}
+	}
+
+	consumed := make([]definition.Param, 0)
+	add := func(typ string) {
+		if slices.Contains(wired, typ) && !slices.ContainsFunc(consumed, func(c definition.Param) bool { return c.Type == typ }) {
+			consumed = append(consumed, definition.Param{Type: typ})
+		}
+	}
+	for _, p := range g.Providers {
+		if p.Existing && len(p.Interfaces) > 0 {
+			add(p.Type)
+		}
+		if p.Existing {
+			continue
+		}
+		for _, prm := range p.Constructor.Params {
+			add(prm.Type)
+		}
+	}
+
+	slices.SortFunc(consumed, func(a, b definition.Param) int {
+		return strings.Compare(a.Type, b.Type)
+	})
+	return consumed
+}
+
+// PrivateInterface tells if the given package interface stays internal to the package module.
+func (g *Graph) PrivateInterface(name string) bool {
+	ifc, ok := g.Package.Interfaces[name]
+	return ok && ifc.Private()
+}
+
+// ProviderOf returns the provider making the given type available, if any. Selected interfaces are
+// attributed to their default implementation.
+func (g *Graph) ProviderOf(typ string) *Provider {
+	for _, p := range g.Providers {
+		if p.Type == typ || slices.Contains(p.Interfaces, typ) {
+			return p
+		}
+	}
+	for _, s := range g.Selectors {
+		if s.Interface != typ {
+			continue
+		}
+		for _, p := range g.Providers {
+			if p.Name == s.Default {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// namer returns a namer clear of the package identifiers and the names generated for the graph.
+func (g *Graph) namer() *namer {
+	taken := slices.Clone(g.Package.Identifiers)
+	taken = append(taken, g.ModuleName)
+	for _, name := range g.ModuleNames {
+		taken = append(taken, name)
+	}
+	for _, c := range g.Configs {
+		taken = append(taken, c.Loader)
+	}
+	for _, d := range g.Decorators {
+		taken = append(taken, d.Name, d.Func)
+	}
+	if g.DecoratorsName != "" {
+		taken = append(taken, g.DecoratorsName)
+	}
+	return newNamer(Naming{}, taken)
+}
+
+// Fallible tells if the provider constructor also returns an error.
+func (p *Provider) Fallible() bool {
+	rs := p.Constructor.Returns
+	return len(rs) == 2 && rs[1].Type == "error"
+}
+
+// Instantiated returns the types built by the providers of instantiated generic structs, whose type
+// arguments may need imports wherever their constructor is referenced.
+func (g *Graph) Instantiated() []definition.Param {
+	var types []definition.Param
+	for _, p := range g.Providers {
+		if len(p.TypeArgs) > 0 {
+			types = append(types, definition.Param{Type: p.Type})
+		}
+	}
+	return types
+}
+
+// RequiredBy returns the constructors of the graph taking the given type, in provider order.
+func (g *Graph) RequiredBy(typ string) []string {
+	var ctors []string
+	for _, p := range g.Providers {
+		if slices.ContainsFunc(p.Constructor.Params, func(prm definition.Param) bool { return prm.Type == typ }) {
+			ctors = append(ctors, p.Constructor.Name)
+		}
+	}
+	return ctors
+}
+
+// External returns the requirements along with the framework types fx does not provide by itself,
+// all of them to be supplied by the application root.
+func (g *Graph) External() []definition.Param {
+	external := slices.Clone(g.Requirements)
+	for _, f := range g.Framework {
+		if !providedByFx(f.Type, g.Package) {
+			external = append(external, f)
+		}
+	}
+	return external
+}
+
+// requirements returns the constructor parameters whose type is not provided by the graph,
+// splitting the framework types apart.
+func (g *Graph) requirements(framework []string) ([]definition.Param, []definition.Param) {
+	provided := g.Provided()
+
+	reqs := make([]definition.Param, 0)
+	fw := make([]definition.Param, 0)
+	for _, p := range g.Providers {
+		for _, prm := range p.Constructor.Params {
+			if slices.Contains(provided, prm.Type) {
+				continue
+			}
+
+			list := &reqs
+			if isFramework(prm.Type, g.Package, framework) {
+				list = &fw
+			}
+			if slices.ContainsFunc(*list, func(r definition.Param) bool { return r.Type == prm.Type }) {
+				continue
+			}
+			*list = append(*list, definition.Param{Type: prm.Type})
+		}
+	}
+
+	for _, list := range [][]definition.Param{reqs, fw} {
+		slices.SortFunc(list, func(a, b definition.Param) int {
+			return strings.Compare(a.Type, b.Type)
+		})
+	}
+	return reqs, fw
+}
+
+// sortedKeys returns the keys of a definition map in ascending order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
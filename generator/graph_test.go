@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstantiationNamesAreUnique(t *testing.T) {
+	g, err := NewGraph(inspect(t, "generic"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string]string)
+	for _, p := range g.Providers {
+		if other, ok := names[p.Name]; ok {
+			t.Fatalf("providers of %s and %s both named %s", other, p.Type, p.Name)
+		}
+		names[p.Name] = p.Type
+	}
+	if names["RepoUser"] != "*RepoUser" || names["RepoUser2"] != "*Repo[User]" {
+		t.Errorf("providers %v, want RepoUser for the struct and RepoUser2 for the instantiation", names)
+	}
+
+	files, err := newFxBackend(Options{}).Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, n := range declarations(t, files[0]) {
+		if n > 1 {
+			t.Errorf("%s declared %d times", name, n)
+		}
+	}
+	module := string(files[0].Content)
+	for _, key := range []string{`"RepoUser":`, `"RepoUser2":`} {
+		if strings.Count(module, key) != 1 {
+			t.Errorf("module key %s not found once:\n%s", key, module)
+		}
+	}
+}
+
+func TestNamedTypeProviders(t *testing.T) {
+	g, err := NewGraph(inspect(t, "named"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Requirements) > 0 {
+		t.Errorf("requirements %v, want none", g.Requirements)
+	}
+
+	files, err := newFxBackend(Options{}).Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = files[0].Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := string(files[0].Content)
+
+	for _, typ := range []string{"Clock", "Port", "Hosts"} {
+		p := g.ProviderOf(typ)
+		if p == nil || p.Constructor.Name != "New"+typ {
+			t.Errorf("%s not provided by New%s", typ, typ)
+		}
+		if !strings.Contains(module, "fx.Provide(\n\t\t\tNew"+typ+",\n") {
+			t.Errorf("New%s not provided by the module:\n%s", typ, module)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsperandio/autofx/analyzer"
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/log"
+)
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(testPluginEnv); mode != "" {
+		os.Exit(testPlugin(mode))
+	}
+
+	level := "error"
+	log.Init(&level)
+	os.Exit(m.Run())
+}
+
+// inspect analyzes the package of the given testdata directory.
+func inspect(t *testing.T, name string) *definition.Package {
+	t.Helper()
+	pkg, err := analyzer.NewInspector().InspectPackage(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+// declarations returns the number of declarations of each top level identifier of a generated file.
+func declarations(t *testing.T, f *File) map[string]int {
+	t.Helper()
+	af, err := parser.ParseFile(token.NewFileSet(), f.Name, f.Content, 0)
+	if err != nil {
+		t.Fatalf("%s: %v", f.Name, err)
+	}
+
+	decls := make(map[string]int)
+	for _, d := range af.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				decls[d.Name.Name]++
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					decls[s.Name.Name]++
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						decls[n.Name]++
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// runGo runs the go command with the given files, relative to the generator directory, laid over the tree.
+func runGo(t *testing.T, files map[string][]byte, args ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir := t.TempDir()
+	replace := make(map[string]string)
+	for name, content := range files {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replace[abs] = filepath.Join(dir, filepath.Base(name))
+		err = os.WriteFile(replace[abs], content, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	overlay, err := json.Marshal(map[string]any{"Replace": replace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "overlay.json"), overlay, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", append([]string{args[0], "-overlay", filepath.Join(dir, "overlay.json")}, args[1:]...)...)
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		for name, content := range files {
+			t.Logf("%s:\n%s", name, content)
+		}
+		t.Fatalf("go %v: %v\n%s", args, err, out)
+	}
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/jsperandio/autofx/log"
+)
+
+// Naming configures the names of the generated module functions, built as Prefix + type name + Suffix.
+// An empty prefix and suffix default to the Module suffix.
+type Naming struct {
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+	// Unexported lower camel cases the names.
+	Unexported bool `json:"unexported,omitempty"`
+}
+
+// namer hands out unique identifiers, resolving collisions with the package identifiers and the names given before.
+type namer struct {
+	naming Naming
+	taken  map[string]bool
+}
+
+func newNamer(naming Naming, identifiers []string) *namer {
+	n := &namer{
+		naming: naming,
+		taken:  make(map[string]bool, len(identifiers)),
+	}
+	for _, id := range identifiers {
+		n.taken[id] = true
+	}
+	return n
+}
+
+// module returns the unique name of the module function providing the given type.
+func (n *namer) module(typ string) string {
+	prefix, suffix := n.naming.Prefix, n.naming.Suffix
+	if prefix == "" && suffix == "" {
+		suffix = "Module"
+	}
+
+	name := prefix + upperCamel(typ) + suffix
+	if n.naming.Unexported {
+		name = lowerCamel(name)
+	}
+	return n.unique(name)
+}
+
+// unique returns the name itself when free, or the name followed by the first free number otherwise.
+func (n *namer) unique(name string) string {
+	resolved := name
+	for i := 2; n.taken[resolved]; i++ {
+		resolved = fmt.Sprintf("%s%d", name, i)
+	}
+
+	if resolved != name {
+		log.Warnf("generated identifier %s collides with an existing one, named %s instead", name, resolved)
+	}
+	n.taken[resolved] = true
+	return resolved
+}
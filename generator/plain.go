@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+	"github.com/jsperandio/autofx/log"
+)
+
+const plainFileName = "build.go"
+
+// plainBackend emits a hand-written style Build function calling every constructor in dependency order,
+// without any DI framework.
+type plainBackend struct {
+	opts Options
+}
+
+func newPlainBackend(opts Options) Backend {
+	return &plainBackend{
+		opts: opts,
+	}
+}
+
+func (b *plainBackend) Generate(g *Graph) ([]*File, error) {
+	order, err := b.sort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	external := g.External()
+	for _, w := range g.HandWired() {
+		if g.ProviderOf(w.Type) == nil && !slices.ContainsFunc(external, func(e definition.Param) bool { return e.Type == w.Type }) {
+			external = append(external, w)
+		}
+	}
+
+	// Build calls the constructors once, so selected interfaces are bound to their default implementation.
+	for _, s := range g.Selectors {
+		log.Warnf("interface %s is selected at runtime with the fx backend only, bound to %s", s.Interface, s.Default)
+	}
+
+	names := g.namer()
+	pd := tmpl.PlainData{
+		PackageName:   g.Package.Name,
+		ContainerName: names.unique("Container"),
+		BuildName:     names.unique("Build"),
+		Fields:        make([]tmpl.PlainField, len(order)),
+		Steps:         make([]tmpl.PlainStep, len(order)),
+	}
+
+	// fx.Lifecycle is stood in for by a lifecycle of the container, the other types fx provides by itself
+	// having no plain counterpart.
+	lifecycles := make(map[string]string)
+	for _, f := range g.Framework {
+		if !providedByFx(f.Type, g.Package) {
+			continue
+		}
+		if canonicalType(f.Type, g.Package) != fxPackage+".Lifecycle" {
+			return nil, fmt.Errorf("the plain backend cannot provide %s to %s", f.Type, strings.Join(g.RequiredBy(f.Type), ", "))
+		}
+		if pd.LifecycleName == "" {
+			pd.LifecycleName = names.unique("lifecycle")
+			pd.HookType = strings.TrimSuffix(f.Type, "Lifecycle") + "Hook"
+		}
+		lifecycles[f.Type] = "c." + pd.LifecycleName
+		external = append(external, f)
+	}
+
+	imports := g.Package.ImportsOf(append(slices.Clone(external), g.Instantiated()...))
+	if pd.LifecycleName != "" {
+		pd.Context = contextImport(g.Package, imports)
+	}
+	pd.Imports = importList(imports)
+
+	reqs := make(map[string]string)
+	params := make([]string, 0, len(external))
+	for _, r := range external {
+		if _, ok := lifecycles[r.Type]; ok {
+			continue
+		}
+		reqs[r.Type] = fmt.Sprintf("p%d", len(params))
+		params = append(params, fmt.Sprintf("p%d %s", len(params), r.Type))
+	}
+	pd.Params = strings.Join(params, ", ")
+
+	resolve := func(typ string) string {
+		if p := g.ProviderOf(typ); p != nil {
+			return "c." + p.Name
+		}
+		if l, ok := lifecycles[typ]; ok {
+			return l
+		}
+		return reqs[typ]
+	}
+
+	for i, p := range order {
+		pd.Fields[i] = tmpl.PlainField{
+			Name: p.Name,
+			Type: p.Type,
+		}
+		pd.Steps[i] = tmpl.PlainStep{
+			Field:       p.Name,
+			Constructor: p.Constructor.Name,
+			Args:        constructorArgs(p, resolve),
+			Fallible:    p.Fallible(),
+		}
+	}
+
+	f := NewFile(plainFileName, g.Package.Path, nil)
+	t, err := loadTemplate(b.opts, "PlainBuild", tmpl.PlainBuild)
+	if err != nil {
+		return nil, err
+	}
+	err = t.Execute(f, pd)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*File{f}, nil
+}
+
+// contextImport adds the context package to the given imports unless the package already imports it,
+// returning its local name.
+func contextImport(pkg *definition.Package, imports map[string]string) string {
+	for name, path := range pkg.Imports {
+		if path == "context" {
+			imports[name] = path
+			return name
+		}
+	}
+	imports["context"] = "context"
+	return "context"
+}
+
+// sort orders the graph providers so every one comes after the providers of its parameters.
+func (b *plainBackend) sort(g *Graph) ([]*Provider, error) {
+	order := make([]*Provider, 0, len(g.Providers))
+	state := make(map[*Provider]int)
+
+	var visit func(p *Provider) error
+	visit = func(p *Provider) error {
+		switch state[p] {
+		case 1:
+			return fmt.Errorf("dependency cycle found at %s", p.Constructor.Name)
+		case 2:
+			return nil
+		}
+
+		state[p] = 1
+		for _, prm := range p.Constructor.Params {
+			dep := g.ProviderOf(prm.Type)
+			if dep == nil {
+				continue
+			}
+			err := visit(dep)
+			if err != nil {
+				return err
+			}
+		}
+		state[p] = 2
+
+		order = append(order, p)
+		return nil
+	}
+
+	for _, p := range g.Providers {
+		err := visit(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
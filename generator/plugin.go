@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/analyzer/parser"
+	"github.com/jsperandio/autofx/log"
+)
+
+// Plugin configures an external generator, run after the backend with the analyzed packages on stdin.
+// It must answer on stdout with a PluginResponse.
+type Plugin struct {
+	Name      string   `json:"name"`
+	Command   string   `json:"command"`
+	Args      []string `json:"args,omitempty"`
+	Parameter string   `json:"parameter,omitempty"`
+}
+
+// PluginRequest is the JSON document written to the plugin stdin.
+type PluginRequest struct {
+	Parameter string          `json:"parameter,omitempty"`
+	Packages  []PluginPackage `json:"packages"`
+}
+
+// PluginPackage is an analyzed package along with its dependency graph.
+type PluginPackage struct {
+	Definition *definition.Package `json:"definition"`
+	Graph      *Graph              `json:"graph"`
+}
+
+// PluginResponse is the JSON document read from the plugin stdout.
+type PluginResponse struct {
+	// Files are written to the package directory unless an error diagnostic is reported.
+	Files       []PluginFile `json:"files,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// PluginFile is a file emitted by a plugin, named relative to the package directory.
+// Go files not starting with parser.GeneratedHeader are given it, so they are left out of the analysis
+// and overwritten by the next runs, like the other generated files.
+type PluginFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// Diagnostic is a message reported by a plugin, with an error, warning or info severity.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Run executes the plugin against the given packages and returns the files it emitted.
+func (p Plugin) Run(pkgs []PluginPackage) ([]*File, error) {
+	req, err := json.Marshal(PluginRequest{
+		Parameter: p.Parameter,
+		Packages:  pkgs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w: %s", p.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp PluginResponse
+	err = json.Unmarshal(stdout.Bytes(), &resp)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid response: %w", p.Name, err)
+	}
+
+	var failed []string
+	for _, d := range resp.Diagnostics {
+		switch d.Severity {
+		case "error":
+			log.Errorf("plugin %s: %s", p.Name, d.Message)
+			failed = append(failed, d.Message)
+		case "warning":
+			log.Warnf("plugin %s: %s", p.Name, d.Message)
+		default:
+			log.Infof("plugin %s: %s", p.Name, d.Message)
+		}
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("plugin %s reported %d errors", p.Name, len(failed))
+	}
+
+	dir := ""
+	if len(pkgs) > 0 {
+		dir = pkgs[0].Definition.Path
+	}
+
+	files := make([]*File, len(resp.Files))
+	for i, f := range resp.Files {
+		name := filepath.Clean(f.Name)
+		if filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+			return nil, fmt.Errorf("plugin %s: file %s outside the package directory", p.Name, f.Name)
+		}
+		content := []byte(f.Content)
+		if filepath.Ext(name) == ".go" && !bytes.HasPrefix(content, []byte(parser.GeneratedHeader)) {
+			content = append([]byte(parser.GeneratedHeader+"\n\n"), content...)
+		}
+		files[i] = NewFile(filepath.Base(name), filepath.Join(dir, filepath.Dir(name)), content)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testPluginEnv selects the behavior of the test binary run as a plugin.
+const testPluginEnv = "AUTOFX_TEST_PLUGIN"
+
+// testPlugin answers the plugin request read from stdin as told by mode, returning the exit code.
+func testPlugin(mode string) int {
+	var req struct {
+		Parameter string `json:"parameter"`
+		Packages  []struct {
+			Definition struct {
+				Name string `json:"name"`
+			} `json:"definition"`
+			Graph struct {
+				Providers []struct {
+					Name string `json:"name"`
+				} `json:"providers"`
+			} `json:"graph"`
+		} `json:"packages"`
+	}
+	err := json.NewDecoder(os.Stdin).Decode(&req)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		return 1
+	}
+
+	var providers []string
+	for _, p := range req.Packages[0].Graph.Providers {
+		providers = append(providers, p.Name)
+	}
+	resp := PluginResponse{
+		Files: []PluginFile{
+			{Name: "plugin.txt", Content: req.Parameter + " " + req.Packages[0].Definition.Name},
+			{Name: "docs/providers.txt", Content: strings.Join(providers, ",")},
+		},
+	}
+
+	switch mode {
+	case "warning":
+		resp.Diagnostics = []Diagnostic{{Severity: "warning", Message: "deprecated parameter"}}
+	case "error":
+		resp.Diagnostics = []Diagnostic{{Severity: "error", Message: "unsupported package"}}
+	case "go":
+		resp.Files = []PluginFile{
+			{Name: "routes.go", Content: "package backends\n\nvar Routes = 1\n"},
+			{Name: "marked.go", Content: "// Code generated by autofx. DO NOT EDIT.\n\npackage backends\n"},
+		}
+	case "escape":
+		resp.Files = []PluginFile{{Name: "../plugin.txt"}}
+	case "exit":
+		fmt.Fprint(os.Stderr, "plugin crashed")
+		return 3
+	case "garbage":
+		fmt.Print("not a response")
+		return 0
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(resp)
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+func TestPlugin(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		files map[string]string
+		err   string
+	}{
+		{
+			name: "files",
+			mode: "files",
+			files: map[string]string{
+				"plugin.txt":         "docs backends",
+				"docs/providers.txt": "DB,Handler,Service",
+			},
+		},
+		{
+			name: "warning diagnostic",
+			mode: "warning",
+			files: map[string]string{
+				"plugin.txt": "docs backends",
+			},
+		},
+		{
+			name: "go files",
+			mode: "go",
+			files: map[string]string{
+				"routes.go": "// Code generated by autofx. DO NOT EDIT.\n\npackage backends\n\nvar Routes = 1\n",
+				"marked.go": "// Code generated by autofx. DO NOT EDIT.\n\npackage backends\n",
+			},
+		},
+		{
+			name: "error diagnostic",
+			mode: "error",
+			err:  "plugin docs reported 1 errors",
+		},
+		{
+			name: "file outside the package",
+			mode: "escape",
+			err:  "plugin docs: file ../plugin.txt outside the package directory",
+		},
+		{
+			name: "non-zero exit",
+			mode: "exit",
+			err:  "plugin docs: exit status 3: plugin crashed",
+		},
+		{
+			name: "bad output",
+			mode: "garbage",
+			err:  "plugin docs: invalid response",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(testPluginEnv, tt.mode)
+
+			pkg := inspect(t, "backends")
+			pkg.Path = t.TempDir()
+			err := NewGenerator(pkg, Options{
+				Plugins: []Plugin{{Name: "docs", Command: os.Args[0], Parameter: "docs"}},
+			}).Generate()
+
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %s", err, tt.err)
+				}
+				_, err = os.Stat(filepath.Join(pkg.Path, "plugin.txt"))
+				if err == nil {
+					t.Error("plugin.txt written despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, want := range tt.files {
+				got, err := os.ReadFile(filepath.Join(pkg.Path, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("%s is %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPluginGoFilesRegenerated(t *testing.T) {
+	t.Setenv(testPluginEnv, "go")
+
+	pkg := inspect(t, "backends")
+	pkg.Path = t.TempDir()
+	for run := 1; run <= 3; run++ {
+		err := NewGenerator(pkg, Options{
+			Plugins: []Plugin{{Name: "routes", Command: os.Args[0]}},
+		}).Generate()
+		if err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+	}
+
+	_, err := os.Stat(filepath.Join(pkg.Path, "routes_autofx.go"))
+	if err == nil {
+		t.Error("routes.go of a previous run taken as hand-written")
+	}
+}
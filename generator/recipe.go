@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"slices"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/analyzer/parser"
+)
+
+// Recipe annotates into a value group every provider whose type implements an interface method set.
+// A recipe named after a built-in one, like routes, takes the fields it leaves empty from it.
+type Recipe struct {
+	// Name names the value group.
+	Name string `json:"name"`
+	// Type names the alias of the interface typing the group members.
+	Type string `json:"type,omitempty"`
+	// Interface is the method set of the members, like
+	// interface{ ServeHTTP(http.ResponseWriter, *http.Request); Pattern() string }.
+	Interface string `json:"interface,omitempty"`
+	// Imports maps the local names of the packages referenced by Interface and Target to their path.
+	Imports map[string]string `json:"imports,omitempty"`
+	// Consumer adds a Register<Name> option invoking Register for every member of the group.
+	Consumer bool `json:"consumer,omitempty"`
+	// Target is the type supplied by the application the members are registered on.
+	Target string `json:"target,omitempty"`
+	// Register is the statement registering a member, given as item, on the target, given as target.
+	Register string `json:"register,omitempty"`
+}
+
+// builtinRecipes are the recipes available by name alone.
+var builtinRecipes = map[string]Recipe{
+	// routes groups the HTTP handlers telling their own pattern, registered on a *http.ServeMux.
+	"routes": {
+		Name:      "routes",
+		Type:      "Route",
+		Interface: "interface{ ServeHTTP(http.ResponseWriter, *http.Request); Pattern() string }",
+		Imports:   map[string]string{"http": "net/http"},
+		Target:    "*http.ServeMux",
+		Register:  "target.Handle(item.Pattern(), item)",
+	},
+}
+
+// resolve fills the fields the recipe leaves empty from the built-in recipe of the same name, if any,
+// and checks the resulting recipe.
+func (r Recipe) resolve() (Recipe, error) {
+	if b, ok := builtinRecipes[r.Name]; ok {
+		if r.Type == "" {
+			r.Type = b.Type
+		}
+		if r.Interface == "" {
+			r.Interface, r.Imports = b.Interface, b.Imports
+		}
+		if r.Target == "" {
+			r.Target = b.Target
+		}
+		if r.Register == "" {
+			r.Register = b.Register
+		}
+	}
+
+	switch {
+	case r.Name == "" || r.Type == "" || r.Interface == "":
+		return r, fmt.Errorf("recipe %q needs a name, a type and an interface", r.Name)
+	case r.Consumer && (r.Target == "" || r.Register == ""):
+		return r, fmt.Errorf("recipe %s needs a target and a register statement for its consumer", r.Name)
+	}
+	return r, nil
+}
+
+// methods parses the method set of the recipe interface.
+func (r Recipe) methods() ([]definition.Method, error) {
+	expr, err := goparser.ParseExpr(r.Interface)
+	if err != nil {
+		return nil, fmt.Errorf("recipe %s interface: %w", r.Name, err)
+	}
+	it, ok := expr.(*ast.InterfaceType)
+	if !ok {
+		return nil, fmt.Errorf("recipe %s interface is not an interface type", r.Name)
+	}
+
+	methods := make([]definition.Method, 0, len(it.Methods.List))
+	for _, f := range it.Methods.List {
+		ft, ok := f.Type.(*ast.FuncType)
+		if !ok || len(f.Names) == 0 {
+			return nil, fmt.Errorf("recipe %s interface embeds a type, only methods are supported", r.Name)
+		}
+
+		m := definition.NewMethod(f.Names[0].Name)
+		m.Params, err = parser.ParseParams(ft.Params)
+		if err != nil {
+			return nil, err
+		}
+		m.Returns, err = parser.ParseParams(ft.Results)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, *m)
+	}
+	return methods, nil
+}
+
+// implementedBy tells if a struct of the given package implements the recipe method set,
+// types being compared by import path.
+func (r Recipe) implementedBy(s *definition.Struct, methods []definition.Method, pkg *definition.Package) bool {
+	own := &definition.Package{Imports: r.Imports}
+	sameTypes := func(a, b definition.Param) bool {
+		return canonicalType(a.Type, own) == canonicalType(b.Type, pkg)
+	}
+
+	for _, m := range methods {
+		i := slices.IndexFunc(s.Methods, func(sm definition.Method) bool { return sm.Name == m.Name })
+		if i < 0 {
+			return false
+		}
+		sm := s.Methods[i]
+		if !slices.EqualFunc(m.Params, sm.Params, sameTypes) || !slices.EqualFunc(m.Returns, sm.Returns, sameTypes) {
+			return false
+		}
+	}
+	return true
+}
+
+// recipes resolves the recipes of the options and annotates into their groups the providers implementing them,
+// returning the recipes with members. Generic structs and hand-written providers are left out.
+func recipes(g *Graph, opts Options) ([]Recipe, error) {
+	var used []Recipe
+	for _, r := range opts.Recipes {
+		r, err := r.resolve()
+		if err != nil {
+			return nil, err
+		}
+		methods, err := r.methods()
+		if err != nil {
+			return nil, err
+		}
+
+		members := 0
+		for _, p := range g.Providers {
+			s, ok := g.Package.Structs[p.Name]
+			if !ok || p.Existing || !r.implementedBy(s, methods, g.Package) {
+				continue
+			}
+			p.Groups = append(p.Groups, r.Name)
+			members++
+		}
+		if members > 0 {
+			used = append(used, r)
+		}
+	}
+	return used, nil
+}
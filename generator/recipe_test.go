@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// funcSource returns the source of the named top level function of a generated file.
+func funcSource(t *testing.T, src string, name string) string {
+	t.Helper()
+	start := strings.Index(src, "func "+name+"(")
+	if start < 0 {
+		t.Fatalf("function %s not found:\n%s", name, src)
+	}
+	end := strings.Index(src[start:], "\n}\n")
+	return src[start : start+end+3]
+}
+
+func TestRoutesRecipe(t *testing.T) {
+	g, err := NewGraph(inspect(t, "routes"), Options{Recipes: []Recipe{{Name: "routes", Consumer: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := newFxBackend(Options{}).Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = files[0].Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := string(files[0].Content)
+
+	tag := "fx.ResultTags(`group:\"routes\"`)"
+	tests := []struct {
+		name   string
+		module string
+		want   bool
+	}{
+		{name: "matching struct", module: "HealthModule", want: true},
+		{name: "struct without pattern", module: "UsersModule", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := funcSource(t, module, tt.module)
+			if strings.Contains(src, tag) != tt.want {
+				t.Errorf("%s annotated into the routes group is %v, want %v:\n%s", tt.module, !tt.want, tt.want, src)
+			}
+		})
+	}
+
+	for _, want := range []string{"type Route = interface {", "func RegisterRoutes() fx.Option {", "target.Handle(item.Pattern(), item)"} {
+		if !strings.Contains(module, want) {
+			t.Errorf("%s not found:\n%s", want, module)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryNames(t *testing.T) {
+	g, err := NewGraph(inspect(t, "backends"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := newFxBackend(Options{Registry: true}).Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = files[0].Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := string(files[0].Content)
+
+	want := `r.Component("github.com/jsperandio/autofx/generator/testdata/backends", "backends", "DB", DBModule())`
+	if !strings.Contains(module, want) {
+		t.Errorf("%s not found:\n%s", want, module)
+	}
+}
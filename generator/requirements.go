@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tmpl "github.com/jsperandio/autofx/generator/template"
+)
+
+const manifestFileName = "requirements.json"
+
+// Manifest is the JSON contract of the types a package module requires from outside the package.
+type Manifest struct {
+	Package      string                `json:"package"`
+	Module       string                `json:"module"`
+	Requirements []ManifestRequirement `json:"requirements"`
+}
+
+// ManifestRequirement is a required type, along with the import path qualifying it, if any,
+// and the constructors requiring it.
+type ManifestRequirement struct {
+	Type       string   `json:"type"`
+	Import     string   `json:"import,omitempty"`
+	RequiredBy []string `json:"requiredBy"`
+}
+
+// contractData builds the requirements contract of the graph, naming each field after its type.
+func contractData(g *Graph, name string) tmpl.ContractData {
+	cd := tmpl.ContractData{
+		Name:       name,
+		ModuleName: g.ModuleName,
+		Fields:     make([]tmpl.ContractFieldData, len(g.Requirements)),
+	}
+
+	fields := newNamer(Naming{}, nil)
+	for i, r := range g.Requirements {
+		cd.Fields[i] = tmpl.ContractFieldData{
+			Name:       fields.unique(fieldName(r.Type)),
+			Type:       r.Type,
+			RequiredBy: g.RequiredBy(r.Type),
+		}
+	}
+	return cd
+}
+
+// manifestFile builds the JSON manifest of the types the graph module requires from outside the package.
+func manifestFile(g *Graph) (*File, error) {
+	m := Manifest{
+		Package:      g.Package.ImportPath,
+		Module:       g.ModuleName,
+		Requirements: make([]ManifestRequirement, len(g.Requirements)),
+	}
+	for i, r := range g.Requirements {
+		m.Requirements[i] = ManifestRequirement{
+			Type:       r.Type,
+			RequiredBy: g.RequiredBy(r.Type),
+		}
+		if q := r.Qualifiers(); len(q) > 0 {
+			m.Requirements[i].Import = g.Package.Imports[q[0]]
+		}
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	return NewFile(manifestFileName, g.Package.Path, append(b, '\n')), nil
+}
+
+// fieldName derives an exported field name from the last identifier of a type, like DB for *sql.DB.
+func fieldName(typ string) string {
+	ids := typeIdentRegexp.FindAllStringSubmatch(typ, -1)
+	if len(ids) == 0 {
+		return "Field"
+	}
+	return upperCamel(ids[len(ids)-1][2])
+}
@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// requirementsContractTest runs the module generated for the requirements testdata package with its contract.
+const requirementsContractTest = `package requirements
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"go.uber.org/fx"
+)
+
+func TestContract(t *testing.T) {
+	db, client := &sql.DB{}, &http.Client{}
+	app := fx.New(
+		Module(),
+		fx.Provide(func() Requirements { return Requirements{Client: client, DB: db} }),
+		fx.Invoke(func(h *Handler) {
+			if h.db != db || h.repo.db != db || h.client != client {
+				t.Error("handler not built from the contract")
+			}
+		}),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+`
+
+func TestRequirementsContract(t *testing.T) {
+	for _, name := range []string{"requirements", "private"} {
+		g, err := NewGraph(inspect(t, name), Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewBackend(Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := b.Generate(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = files[0].Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := 0
+		if len(g.Requirements) > 0 {
+			want = 1
+		}
+		if n := declarations(t, files[0])["Requirements"]; n != want {
+			t.Errorf("%s: Requirements declared %d times, want %d:\n%s", name, n, want, files[0].Content)
+		}
+		if name != "requirements" {
+			continue
+		}
+
+		dir := filepath.Join("testdata", name)
+		runGo(t, map[string][]byte{
+			filepath.Join(dir, files[0].Name):          files[0].Content,
+			filepath.Join(dir, "requirements_test.go"): []byte(requirementsContractTest),
+		}, "test", "-vet=off", "./"+dir)
+	}
+}
+
+func TestManifestFile(t *testing.T) {
+	g, err := NewGraph(inspect(t, "requirements"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := manifestFile(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "requirements.json" {
+		t.Errorf("manifest saved as %s", f.Name)
+	}
+
+	var got Manifest
+	err = json.Unmarshal(f.Content, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Manifest{
+		Package: "github.com/jsperandio/autofx/generator/testdata/requirements",
+		Module:  "Module",
+		Requirements: []ManifestRequirement{
+			{Type: "*http.Client", Import: "net/http", RequiredBy: []string{"NewHandler"}},
+			{Type: "*sql.DB", Import: "database/sql", RequiredBy: []string{"NewHandler", "NewRepo"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("manifest %+v, want %+v", got, want)
+	}
+}
+
+func TestManifestFrameworkTypes(t *testing.T) {
+	g, err := NewGraph(inspect(t, "framework"), Options{FrameworkTypes: []string{"*net/http.Client"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := manifestFile(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Manifest
+	err = json.Unmarshal(f.Content, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ManifestRequirement{{Type: "*sql.DB", Import: "database/sql", RequiredBy: []string{"NewServer"}}}
+	if !reflect.DeepEqual(got.Requirements, want) {
+		t.Errorf("manifest requirements %+v, want %+v", got.Requirements, want)
+	}
+
+	var external []string
+	for _, p := range g.External() {
+		external = append(external, p.Type)
+	}
+	wantExternal := []string{"*sql.DB", "*http.Client", "*slog.Logger", "context.Context"}
+	if !reflect.DeepEqual(external, wantExternal) {
+		t.Errorf("external types %v, want %v", external, wantExternal)
+	}
+}
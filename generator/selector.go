@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+)
+
+// Selector provides an interface with one of its implementations, selected at runtime by key.
+type Selector struct {
+	Interface string `json:"interface"`
+	// Env names the variable holding the key, if any.
+	Env string `json:"env,omitempty"`
+	// Config and Field name the string field of a config struct of the graph holding the key, if any.
+	Config string `json:"config,omitempty"`
+	Field  string `json:"field,omitempty"`
+	// Default is the implementation selected by an empty key.
+	Default         string      `json:"default"`
+	Implementations []Selection `json:"implementations"`
+}
+
+// Selection is an implementation of a selected interface, by key.
+type Selection struct {
+	Key      string `json:"key"`
+	Provider string `json:"provider"`
+	Type     string `json:"type"`
+}
+
+// selected tells if the interface is provided by a selector, either marked with //autofx:select or
+// listed in the options selectors, returning the source of the key, if any: an env variable or
+// the field of a config struct, like Settings.StoreKind.
+func selected(ifc *definition.Interface, opts Options) (string, bool) {
+	if env, ok := opts.Selectors[ifc.Type()]; ok {
+		return env, true
+	}
+	env, ok := ifc.Directives["select"]
+	return env, ok
+}
+
+// newSelector builds the selector of an interface among the given providers, keyed by their //autofx:key
+// directive, or else by their struct name lower cased and stripped of the interface name, like mem for MemStore.
+// The key is read from the given source, either an env variable or the string field of one of the configs.
+func newSelector(ifc *definition.Interface, source, bound string, providers []*Provider, configs []Config, pkg *definition.Package) (Selector, error) {
+	sel := Selector{
+		Interface:       ifc.Type(),
+		Env:             source,
+		Default:         bound,
+		Implementations: make([]Selection, 0, len(providers)),
+	}
+	if cfg, field, ok := strings.Cut(source, "."); ok {
+		if !hasStringField(configs, cfg, field) {
+			return sel, fmt.Errorf("interface %s selector key %s is not a string field of a config struct", ifc.Type(), source)
+		}
+		sel.Env, sel.Config, sel.Field = "", cfg, field
+	}
+
+	keys := make(map[string]string, len(providers))
+	for _, p := range providers {
+		key, ok := pkg.Structs[p.Name].Directives["key"]
+		if !ok || key == "" {
+			key = strings.ToLower(p.Name)
+			if trimmed := strings.TrimSuffix(key, strings.ToLower(ifc.Type())); trimmed != "" {
+				key = trimmed
+			}
+		}
+		if other, ok := keys[key]; ok {
+			return sel, fmt.Errorf("interface %s implementations %s and %s share the selector key %s", ifc.Type(), other, p.Name, key)
+		}
+		keys[key] = p.Name
+
+		sel.Implementations = append(sel.Implementations, Selection{
+			Key:      key,
+			Provider: p.Name,
+			Type:     p.Type,
+		})
+	}
+	return sel, nil
+}
+
+// hasStringField tells if the config struct of the given name has a string field of the given name.
+func hasStringField(configs []Config, name, field string) bool {
+	for _, c := range configs {
+		if c.Struct != name {
+			continue
+		}
+		return slices.ContainsFunc(c.Fields, func(f definition.Field) bool {
+			return f.Name == field && f.Type == "string"
+		})
+	}
+	return false
+}
+
+// Keys returns the selector keys, in implementation order.
+func (s Selector) Keys() []string {
+	keys := make([]string, len(s.Implementations))
+	for i, impl := range s.Implementations {
+		keys[i] = impl.Key
+	}
+	return keys
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+)
+
+// selectablePackage declares a public and a private interface, both selected at runtime and decorated,
+// each with two implementations.
+func selectablePackage() *definition.Package {
+	pkg := definition.NewPackage("store", "store")
+	for _, ifc := range []string{"Store", "cache"} {
+		i := definition.NewInterface(ifc)
+		i.Directives = map[string]string{"select": "", "decorate": ""}
+		for _, impl := range []string{"Mem", "Redis"} {
+			name := impl + upperCamel(ifc)
+			ctor := definition.NewFunction("New" + name)
+			ctor.Returns = []definition.Param{{Type: "*" + name}}
+
+			s := definition.NewStruct(name)
+			s.Constructors = []definition.Function{*ctor}
+			pkg.Structs[name] = s
+			i.Implementations = append(i.Implementations, name)
+		}
+		pkg.Interfaces[ifc] = i
+	}
+	return pkg
+}
+
+func TestSelectedInterfacesAreDecorated(t *testing.T) {
+	g, err := NewGraph(selectablePackage(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := newFxBackend(Options{}).Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := string(files[0].Content)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "public selector", want: `"Store": selectStore(""),`},
+		{name: "public decorator at the root", want: "func Decorators() fx.Option"},
+		{name: "public decorator applied", want: "DecorateStore,"},
+		{name: "private selector", want: `"cache": selectCache(""),`},
+		{name: "private decorator with the selection", want: "decorateCache,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(module, tt.want) {
+				t.Errorf("module misses %s:\n%s", tt.want, module)
+			}
+		})
+	}
+
+	if strings.Count(module, "decorateCache,") != 2 {
+		t.Errorf("decorateCache not applied with every cache implementation:\n%s", module)
+	}
+	if strings.Count(module, "DecorateStore,") != 1 {
+		t.Errorf("DecorateStore applied more than once:\n%s", module)
+	}
+	if len(files) != 2 || files[1].Name != decoratorFileName {
+		t.Errorf("decorators not generated, files %d", len(files))
+	}
+}
+
+// configSelectorTest runs the module generated for the selectors testdata package, selecting the Store by config.
+const configSelectorTest = `package selectors
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/fx"
+)
+
+func TestConfigSelector(t *testing.T) {
+	tests := []struct {
+		kind string
+		opts []Option
+		want string
+		err  string
+	}{
+		{kind: "", want: "mem"},
+		{kind: "disk", want: "disk"},
+		{kind: "disk", opts: []Option{SelectStore("mem")}, want: "mem"},
+		{kind: "tape", err: "unknown Store implementation \"tape\", valid keys: disk, mem"},
+	}
+	for _, tt := range tests {
+		t.Setenv("SELECTORS_STORE_KIND", tt.kind)
+		var got string
+		err := fx.New(Module(tt.opts...), fx.Invoke(func(s Store) { got = s.Name() }), fx.NopLogger).Err()
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("kind %q: error %v, want %s", tt.kind, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("kind %q: %v", tt.kind, err)
+		}
+		if got != tt.want {
+			t.Errorf("kind %q selected %s, want %s", tt.kind, got, tt.want)
+		}
+	}
+}
+`
+
+func TestConfigSelector(t *testing.T) {
+	g, err := NewGraph(inspect(t, "selectors"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := newFxBackend(Options{}).Generate(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf, err := configFile(g, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join("testdata", "selectors")
+	overlay := map[string][]byte{filepath.Join(dir, "selectors_test.go"): []byte(configSelectorTest)}
+	for _, f := range append(files, cf) {
+		err = f.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+		overlay[filepath.Join(dir, f.Name)] = f.Content
+	}
+	runGo(t, overlay, "test", "-vet=off", "./"+dir)
+}
+
+func TestConfigSelectorKeyField(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{name: "string field", source: "StoreConfig.Kind"},
+		{name: "field of another type", source: "StoreConfig.Retries", err: "selector key StoreConfig.Retries is not a string field"},
+		{name: "missing field", source: "StoreConfig.Mode", err: "selector key StoreConfig.Mode is not a string field"},
+		{name: "not a config struct", source: "MemStore.Kind", err: "selector key MemStore.Kind is not a string field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGraph(inspect(t, "selectors"), Options{Selectors: map[string]string{"Store": tt.source}})
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
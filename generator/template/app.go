@@ -0,0 +1,50 @@
+package template
+
+// AppData is the data of the AppMain template, its names and types being qualified as referenced from the main package.
+type AppData struct {
+	Imports []Import
+	// Modules holds the module functions of the packages.
+	Modules []string
+	// Invokes holds the functions marked with //autofx:invoke.
+	Invokes []string
+	// Requirements holds the types no analyzed package provides.
+	Requirements []string
+}
+
+const (
+	AppMain = `// Bootstrapped by autofx from the application packages, to be maintained by hand from now on.
+
+package main
+
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+
+func main() {
+	fx.New(
+	{{range .Modules}}	{{.}}(),
+	{{end}}	requirements(),{{ if .Invokes }}
+		fx.Invoke(
+		{{range .Invokes}}	{{.}},
+		{{end}}),{{end}}
+		fx.WithLogger(eventLogger),
+	).Run()
+}
+
+// eventLogger logs the fx events to the standard error, to be replaced by the application logger.
+func eventLogger() fxevent.Logger {
+	return &fxevent.ConsoleLogger{W: os.Stderr}
+}
+
+// requirements stands in for the types the modules require from outside the application,
+// failing the start until replaced by actual providers.
+func requirements() fx.Option {
+	return fx.Options(
+	{{range .Requirements}}	fx.Provide(func() ({{.}}, error) {
+			var v {{.}}
+			return v, errors.New("{{.}} is not provided")
+		}),
+	{{end}})
+}
+`
+)
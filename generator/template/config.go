@@ -0,0 +1,76 @@
+package template
+
+// ConfigData is the data of the ConfigLoader template.
+type ConfigData struct {
+	PackageName string
+	Imports     []Import
+	// Lookup names the helper reading an environment variable with its default.
+	Lookup  string
+	Loaders []LoaderData
+}
+
+// LoaderData describes the function loading a configuration struct from the environment.
+type LoaderData struct {
+	Name   string
+	Struct string
+	Fields []ConfigFieldData
+}
+
+// ConfigFieldData describes how a configuration field is read from the environment.
+type ConfigFieldData struct {
+	Name    string
+	Env     string
+	Default string
+	// HasDefault tells if the field is tagged with a default, even an empty one.
+	HasDefault bool
+	Required   bool
+	// Parse is the expression converting the variable value v, returning an error as well when Fallible.
+	Parse    string
+	Fallible bool
+	// Convert is the conversion applied to the parsed value, if any.
+	Convert string
+}
+
+const (
+	ConfigLoader = `// Code generated by autofx. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+{{range .Loaders}}
+// {{.Name}} loads the {{.Struct}} configuration from the environment, falling back to the field defaults.
+func {{.Name}}() (*{{.Struct}}, error) {
+	c := &{{.Struct}}{}
+	var errs []error
+{{range .Fields}}
+	if v, ok := {{$.Lookup}}({{printf "%q" .Env}}, {{printf "%q" .Default}}, {{.HasDefault}}); ok {
+{{- if .Fallible}}
+		x, err := {{.Parse}}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("{{.Env}}: %w", err))
+		}
+		c.{{.Name}} = {{if .Convert}}{{.Convert}}(x){{else}}x{{end}}
+{{- else}}
+		c.{{.Name}} = {{if .Convert}}{{.Convert}}({{.Parse}}){{else}}{{.Parse}}{{end}}
+{{- end}}
+	}{{if .Required}} else {
+		errs = append(errs, errors.New("{{.Env}} is required"))
+	}{{end}}
+{{end}}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+{{end}}
+// {{.Lookup}} returns the value of an environment variable, or the given default when unset or empty.
+func {{.Lookup}}(key string, def string, hasDef bool) (string, bool) {
+	if v := os.Getenv(key); v != "" {
+		return v, true
+	}
+	return def, hasDef
+}
+`
+)
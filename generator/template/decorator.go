@@ -0,0 +1,46 @@
+package template
+
+// DecoratorMethodData is a decorated method, along with the name of its error result, if any.
+type DecoratorMethodData struct {
+	FakeMethodData
+	Err string
+}
+
+// DecoratorData is the data of the Decorator template.
+type DecoratorData struct {
+	Interface string
+	// Name names the decorator type.
+	Name string
+	// FuncName names the function wiring the decorator.
+	FuncName string
+	Methods  []DecoratorMethodData
+}
+
+const (
+	Decorator = `
+// {{.Name}} decorates a {{.Interface}}, reporting every call to its hook before delegating to Inner.
+type {{.Name}} struct {
+	Inner {{.Interface}}
+	Hook  hook.Hook
+}
+
+// {{.FuncName}} wraps the given {{.Interface}} with a {{.Name}}, unless no hook is given.
+func {{.FuncName}}(inner {{.Interface}}, h hook.Hook) {{.Interface}} {
+	if h == nil {
+		return inner
+	}
+	return &{{.Name}}{Inner: inner, Hook: h}
+}
+{{range .Methods}}
+func (d *{{$.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	call := hook.Begin(d.Hook, "{{$.Interface}}", "{{.Name}}"{{if .Record}}, {{.Record}}{{end}})
+	{{- if .Err}}
+	defer func() { call.End({{.Err}}) }()
+	{{- else}}
+	defer call.End(nil)
+	{{- end}}
+
+	{{if .Results}}return {{end}}d.Inner.{{.Name}}({{.Args}})
+}
+{{end}}`
+)
@@ -0,0 +1,47 @@
+// Package template holds the default templates of the generator backends.
+//
+// Every template can be overridden by name, either by a <Name>.tmpl file in the
+// template directory or by a file referenced from the configuration:
+//
+//	{
+//	  "generator": {
+//	    "templateDir": "autofx/templates",
+//	    "templates": {"ProviderModule": "autofx/provider.tmpl"}
+//	  }
+//	}
+//
+// The fx backend templates receive the following data:
+//
+//	GoFileInits          FileData
+//	GroupRecipe          RecipeData
+//	ProviderModule       ProviderData
+//	RequirementsContract ContractData
+//	Selector             SelectorData
+//	PackageModule        PackageData
+//	RootDecorators       RootDecoratorsData
+//	Registry             RegistryData
+//	InterfaceAssertions  []AssertionData
+//	TestHarness          TestData
+//	FakeFileInits        FakesData
+//	Fake                 FakeData
+//	TestModule           FakesData
+//	Decorator            DecoratorData
+//
+// The former SimpleModule and InterfaceModule names still override ProviderModule. Overriding a template
+// by any other name is an error.
+//
+// The wire backend WireSet template receives WireData and the plain backend PlainBuild template receives PlainData,
+// while the WireImport template of the import-wire command receives WireImportData and the AppMain template
+// of the app command receives AppData. Whatever the backend, the ConfigLoader template receives ConfigData.
+//
+// Besides the text/template builtins, templates can call:
+//
+//	qualify TYPE PKG  qualifies a package type, qualify "*UserDB" "example" is "*example.UserDB"
+//	baseType TYPE     strips pointer notation, baseType "*UserDB" is "UserDB"
+//	lowerCamel NAME   lower cases the first letter, lowerCamel "UserDB" is "userDB"
+//	upperCamel NAME   upper cases the first letter, upperCamel "userDB" is "UserDB"
+//	join LIST SEP     joins a list of strings
+//	hasPrefix S P     reports if S starts with P
+//	trimPrefix S P    removes the P prefix of S
+//	params LIST       formats a definition.Param list as "name type, ..."
+package template
@@ -0,0 +1,90 @@
+package template
+
+type FakeMethodData struct {
+	Name     string
+	Params   string
+	Args     string
+	Record   string
+	FuncType string
+	Results  string
+}
+
+// FakeData is the data of the Fake template.
+type FakeData struct {
+	Interface string
+	// Name names the fake type.
+	Name string
+	// WithName names the option replacing the interface of the module with the fake, empty when the
+	// interface is private.
+	WithName string
+	Provided bool
+	Methods  []FakeMethodData
+}
+
+// FakesData is the data of the fake file templates.
+type FakesData struct {
+	PackageName string
+	ModuleName  string
+	// TestModuleName names the function composing the module with fakes.
+	TestModuleName string
+	Imports        []Import
+	Fakes          []FakeData
+}
+
+const (
+	FakeFileInits = `// Code generated by autofx. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+`
+
+	Fake = `
+// {{.Name}} is a fake implementation of {{.Interface}}.
+// Each method calls the matching function field, when set, and records its arguments.
+type {{.Name}} struct {
+	mu sync.Mutex
+{{range .Methods}}
+	{{.Name}}Func  {{.FuncType}}
+	{{.Name}}Calls [][]any
+{{- end}}
+}
+{{range .Methods}}
+func (f *{{$.Name}}) {{.Name}}({{.Params}}) {{.Results}} {
+	f.mu.Lock()
+	f.{{.Name}}Calls = append(f.{{.Name}}Calls, []any{ {{- .Record -}} })
+	fn := f.{{.Name}}Func
+	f.mu.Unlock()
+
+	if fn == nil {
+		return
+	}
+	{{if .Results}}return {{end}}fn({{.Args}})
+}
+{{end}}{{ if .WithName }}
+// {{.WithName}} replaces the {{.Interface}} of the module with the given fake.
+func {{.WithName}}(f *{{.Name}}) fx.Option {
+{{- if .Provided}}
+	return fx.Decorate(func({{.Interface}}) {{.Interface}} {
+		return f
+	})
+{{- else}}
+	return fx.Provide(func() {{.Interface}} {
+		return f
+	})
+{{- end}}
+}
+{{end}}`
+
+	TestModule = `
+// {{.TestModuleName}} composes {{.ModuleName}} with the given fakes, so tests can override single dependencies.
+func {{.TestModuleName}}(fakes ...fx.Option) fx.Option {
+	return fx.Options(
+		{{.ModuleName}}(),
+		fx.Options(fakes...),
+	)
+}
+`
+)
@@ -0,0 +1,28 @@
+package template
+
+type Import struct {
+	Alias string
+	Path  string
+}
+
+type InterfaceData struct {
+	PackageName string
+	Imports     []Import
+	Name        string
+	Source      string
+	Methods     []string
+}
+
+const (
+	ExtractedInterface = `package {{.PackageName}}
+{{if .Imports}}
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+{{end}}
+// {{.Name}} is the interface extracted from the {{.Source}} method set.
+type {{.Name}} interface {
+{{range .Methods}}	{{.}}
+{{end}}}
+`
+)
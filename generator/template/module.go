@@ -1,45 +1,352 @@
 package template
 
+import "github.com/jsperandio/autofx/analyzer/definition"
+
 type ModuleData struct {
-	PackageName          string
-	ConstructorName      string
-	ImplementPackageName string
-	ImplementType        string
+	PackageName     string
+	ConstructorName string
+}
+
+// FileData is the data of the GoFileInits template.
+type FileData struct {
+	PackageName string
+	Imports     []Import
+}
+
+// ProviderData is the data of the ProviderModule template.
+// Besides the ModuleData fields, it exposes the provider as analyzed.
+type ProviderData struct {
+	ModuleData
+	// ModuleName is the name of the generated module function.
+	ModuleName string
+	// Struct is the name of the provided struct.
+	Struct string
+	// Type is the type built by the constructor, like *UserDB.
+	Type string
+	// Interfaces are the bound interfaces sharing the visibility of Type, provided along with it.
+	Interfaces []string
+	// Forwarded are the bound interfaces of the opposite visibility, forwarded from the constructed Type.
+	Forwarded []string
+	// Constructor is the analyzed constructor, with its parameters and results.
+	Constructor definition.Function
+	// Fallible tells if the constructor also returns an error.
+	Fallible bool
+	// Private tells if the constructed type stays internal to the package module.
+	Private bool
+	// Existing tells if the constructor is provided by hand-written wiring, the module only binding the interfaces.
+	Existing bool
+	// Decorators name the functions decorating the bound private interfaces, within the package module.
+	Decorators []string
+	// Groups are the value groups the constructed Type is annotated into.
+	Groups []GroupData
+	// WithName and WithoutName name the options replacing and dropping the provider from the package module.
+	WithName    string
+	WithoutName string
+}
+
+// GroupData is a value group of a provider, with the alias typing its members and the group struct tag.
+type GroupData struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// RecipeData is the data of the GroupRecipe template, declaring the alias typing the members of a value group.
+type RecipeData struct {
+	GroupData
+	Interface string
+	// ConsumerName, when set, names the option registering the members on Target.
+	ConsumerName string
+	Target       string
+	Register     string
+}
+
+// PackageData is the data of the PackageModule template.
+type PackageData struct {
+	PackageName string
+	Path        string
+	// ModuleName is the name of the function composing the package modules, Module unless already declared.
+	ModuleName string
+	// OptionName is the name of the option type accepted by the ModuleName function.
+	OptionName string
+	// Modules holds every provider module generated before, in order.
+	Modules []ProviderData
+	// Requirements holds the types the module requires from outside the package.
+	Requirements []definition.Param
+	// Selectors holds the interfaces whose implementation is selected at runtime, composed after the Modules.
+	Selectors []SelectorData
+}
+
+// SelectorData is the data of the Selector template.
+type SelectorData struct {
+	Interface  string
+	ModuleName string
+	OptionName string
+	// OptionFunc names the option selecting the implementation of Interface.
+	OptionFunc string
+	// FuncName names the function providing the implementation by key.
+	FuncName string
+	// Env names the variable holding the key, if any.
+	Env string
+	// Loader names the function loading the config struct whose Field holds the key, if any, called by ConfigFunc.
+	Loader     string
+	Field      string
+	ConfigFunc string
+	// Default is the key selected by an empty one.
+	Default         string
+	Keys            []string
+	Private         bool
+	Implementations []SelectionData
+	// Decorator names the function decorating a private Interface, decorated along with the selected implementation.
+	Decorator string
+}
+
+// SelectionData is an implementation of a selected interface, by key.
+type SelectionData struct {
+	Key  string
+	Type string
+}
+
+// RootDecoratorsData is the data of the RootDecorators template, naming the function applying the decorators
+// of the public interfaces of ModuleName at the root of the application.
+type RootDecoratorsData struct {
+	FuncName   string
+	ModuleName string
+	// Funcs holds the decorator functions.
+	Funcs []string
+}
+
+// RegistryData is the data of the Registry template, naming the function registering the provider modules
+// and selectors of the package under its import path and name.
+type RegistryData struct {
+	ImportPath  string
+	PackageName string
+	FuncName    string
+	Modules     []ProviderData
+	Selectors   []SelectorData
+}
+
+// ContractData is the data of the RequirementsContract template, naming the fx.Out struct
+// through which the parent application provides the types required by the package module.
+type ContractData struct {
+	Name       string
+	ModuleName string
+	Fields     []ContractFieldData
+}
+
+// ContractFieldData is a required type, with the constructors requiring it.
+type ContractFieldData struct {
+	Name       string
+	Type       string
+	RequiredBy []string
+}
+
+type AssertionData struct {
+	Interface string
+	Value     string
 }
 
 const (
-	GoFileInits = `package {{.}}
+	GoFileInits = `// Code generated by autofx. DO NOT EDIT.
+
+package {{.PackageName}}
 
-import "go.uber.org/fx"
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
 `
 
-	InterfaceModule = `
-func {{.ImplementType}}Module() fx.Option {
-	return fx.Options(
+	ProviderModule = `
+func {{.ModuleName}}() fx.Option {
+	return fx.Options({{ if not .Existing }}
+		fx.Provide({{ if .Interfaces }}
+			fx.Annotate(
+				{{.ConstructorName}},
+				fx.As(fx.Self()),{{range .Interfaces}}
+				fx.As(new({{.}})),{{end}}
+			),{{else}}
+			{{.ConstructorName}},{{end}}{{ if .Private }}
+			fx.Private,{{end}}
+		),{{ else if .Interfaces }}
 		fx.Provide(
 			fx.Annotate(
-				{{ if .PackageName }}{{.PackageName}}.{{end}}{{.ConstructorName}},
-				fx.As(new({{ if .ImplementPackageName }}{{.ImplementPackageName}}.{{end}}{{.ImplementType}})),
+				func(v {{.Type}}) {{.Type}} { return v },{{range .Interfaces}}
+				fx.As(new({{.}})),{{end}}
 			),
-		),
+		),{{end}}{{ if .Forwarded }}
+		fx.Provide(
+			fx.Annotate(
+				func(v {{.Type}}) {{.Type}} { return v },{{range .Forwarded}}
+				fx.As(new({{.}})),{{end}}
+			),{{ if not .Private }}
+			fx.Private,{{end}}
+		),{{end}}{{range .Decorators}}
+		fx.Decorate(
+			fx.Annotate(
+				{{.}},
+				fx.ParamTags("", ` + "`optional:\"true\"`" + `),
+			),
+		),{{end}}{{range .Groups}}
+		fx.Provide(
+			fx.Annotate(
+				func(v {{$.Type}}) {{.Type}} { return v },
+				fx.ResultTags({{.Tag}}),
+			),
+		),{{end}}
 	)
 }
 `
 
-	SimpleModule = `
-func {{.ImplementType}}Module() fx.Option {
-	return fx.Options(
-		fx.Provide(
-			{{.ConstructorName}},
+	GroupRecipe = `
+// {{.Type}} is a member of the {{.Name}} value group.
+type {{.Type}} = {{.Interface}}
+{{ if .ConsumerName }}
+// {{.ConsumerName}} registers every member of the {{.Name}} group on the {{.Target}} of the application.
+// Include it once per application, as the group gathers the members of every package.
+func {{.ConsumerName}}() fx.Option {
+	return fx.Invoke(
+		fx.Annotate(
+			func(target {{.Target}}, items []{{.Type}}) {
+				for _, item := range items {
+					{{.Register}}
+				}
+			},
+			fx.ParamTags("", {{.Tag}}),
 		),
 	)
 }
+{{end}}`
+
+	Selector = `
+// {{.OptionFunc}} selects the {{.Interface}} implementation of {{.ModuleName}} by key, among {{join .Keys ", "}}.
+func {{.OptionFunc}}(key string) {{.OptionName}} {
+	return func(m map[string]fx.Option) {
+		m["{{.Interface}}"] = {{.FuncName}}(key)
+	}
+}
+
+// {{.FuncName}} provides the {{.Interface}} implementation of the given key, {{.Default}} when empty.
+{{- if .Env}}
+// {{.ModuleName}} reads the key from {{.Env}} unless selected by {{.OptionFunc}}.
+{{- else if .ConfigFunc}}
+// {{.ModuleName}} reads the key through {{.ConfigFunc}} unless selected by {{.OptionFunc}}.
+{{- end}}
+func {{.FuncName}}(key string) fx.Option {
+	switch key {
+	{{range .Implementations}}case {{ if eq .Key $.Default }}"", {{end}}"{{.Key}}":{{ if $.Decorator }}
+		return fx.Options(
+			fx.Provide(
+				func(v {{.Type}}) {{$.Interface}} { return v },
+				fx.Private,
+			),
+			fx.Decorate(
+				fx.Annotate(
+					{{$.Decorator}},
+					fx.ParamTags("", ` + "`optional:\"true\"`" + `),
+				),
+			),
+		){{else}}
+		return fx.Provide(
+			func(v {{.Type}}) {{$.Interface}} { return v },{{ if $.Private }}
+			fx.Private,{{end}}
+		){{end}}
+	{{end}}}
+	return fx.Error(fmt.Errorf("unknown {{.Interface}} implementation %q, valid keys: {{join .Keys ", "}}", key))
+}
+{{- if .ConfigFunc}}
+
+// {{.ConfigFunc}} provides the {{.Interface}} implementation keyed by the {{.Field}} field of the configuration
+// loaded by {{.Loader}}, when composing {{.ModuleName}}.
+func {{.ConfigFunc}}() fx.Option {
+	c, err := {{.Loader}}()
+	if err != nil {
+		return fx.Error(err)
+	}
+	return {{.FuncName}}(c.{{.Field}})
+}
+{{- end}}
+`
+
+	RootDecorators = `
+// {{.FuncName}} decorates the interfaces provided by {{.ModuleName}} for the whole application.
+// Include it once at the root of the application: fx.Decorate only applies to the module it is given to
+// and to the modules nested in it.
+func {{.FuncName}}() fx.Option {
+	return fx.Options({{range .Funcs}}
+		fx.Decorate(
+			fx.Annotate(
+				{{.}},
+				fx.ParamTags("", ` + "`optional:\"true\"`" + `),
+			),
+		),{{end}}
+	)
+}
+`
+
+	Registry = `
+// {{.FuncName}} adds the providers and selectors of the package to the given registry, named {{.ImportPath}}.<type>
+// or {{.PackageName}}.<type>, to compose applications at runtime.
+func {{.FuncName}}(r *compose.Registry) {
+{{- range .Modules}}
+	r.Component("{{$.ImportPath}}", "{{$.PackageName}}", "{{.Struct}}", {{.ModuleName}}())
+{{- end}}
+{{- range .Selectors}}
+	r.Binding("{{$.ImportPath}}", "{{$.PackageName}}", "{{.Interface}}", {{.FuncName}})
+{{- end}}
+}
+`
+
+	InterfaceAssertions = `
+var (
+{{range .}}	_ {{.Interface}} = {{.Value}}
+{{end}})
+`
+
+	RequirementsContract = `
+// {{.Name}} is the contract of the types {{.ModuleName}} requires from outside the package,
+// to be provided by the parent application:
+//
+{{range .Fields}}//	{{.Type}} required by {{join .RequiredBy ", "}}
+{{end}}//
+// Provide it along with the module, like fx.Provide(func() {{.Name}} { return {{.Name}}{...} }).
+type {{.Name}} struct {
+	fx.Out
+
+{{range .Fields}}	{{.Name}} {{.Type}}
+{{end}}}
 `
 
 	PackageModule = `
-func Module() fx.Option {
-	return fx.Options(
-	{{range .}}	{{ if .ImplementPackageName }}{{.ImplementPackageName}}.{{end}}{{.ImplementType}}Module(),
+// {{.OptionName}} drops or replaces the providers composed by {{.ModuleName}}.
+type {{.OptionName}} func(map[string]fx.Option)
+{{range .Modules}}
+// {{.WithoutName}} drops the {{.Struct}} provider from {{$.ModuleName}}.
+func {{.WithoutName}}() {{$.OptionName}} {
+	return func(m map[string]fx.Option) {
+		m["{{.Struct}}"] = fx.Options()
+	}
+}
+
+// {{.WithName}} replaces the {{.Struct}} provider of {{$.ModuleName}} with the given option.
+func {{.WithName}}(o fx.Option) {{$.OptionName}} {
+	return func(m map[string]fx.Option) {
+		m["{{.Struct}}"] = o
+	}
+}
+{{end}}
+func {{.ModuleName}}(opts ...{{.OptionName}}) fx.Option {
+	modules := map[string]fx.Option{
+	{{range .Modules}}	"{{.Struct}}": {{.ModuleName}}(),
+	{{end}}{{range .Selectors}}	"{{.Interface}}": {{ if .ConfigFunc }}{{.ConfigFunc}}(){{else}}{{.FuncName}}({{ if .Env }}os.Getenv("{{.Env}}"){{else}}""{{end}}){{end}},
+	{{end}}}
+	for _, o := range opts {
+		o(modules)
+	}
+
+	return fx.Module(
+		"{{.PackageName}}",
+	{{range .Modules}}	modules["{{.Struct}}"],
+	{{end}}{{range .Selectors}}	modules["{{.Interface}}"],
 	{{end}})
 }
 `
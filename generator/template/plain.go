@@ -0,0 +1,107 @@
+package template
+
+type PlainField struct {
+	Name string
+	Type string
+}
+
+type PlainStep struct {
+	Field       string
+	Constructor string
+	Args        string
+	Fallible    bool
+}
+
+// PlainData is the data of the PlainBuild template.
+type PlainData struct {
+	PackageName string
+	// ContainerName names the struct holding every built value.
+	ContainerName string
+	// BuildName names the function filling the container.
+	BuildName string
+	// LifecycleName, when set, names the type collecting the HookType hooks of the constructors taking the
+	// fx lifecycle, embedded into the container to start and stop them.
+	LifecycleName string
+	HookType      string
+	// Context is the local name of the context package.
+	Context string
+	Imports []Import
+	Fields  []PlainField
+	Params  string
+	Steps   []PlainStep
+}
+
+const (
+	PlainBuild = `// Code generated by autofx. DO NOT EDIT.
+
+package {{.PackageName}}
+{{if .Imports}}
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+{{end}}
+// {{.ContainerName}} holds every value built by {{.BuildName}}.
+type {{.ContainerName}} struct {
+{{- if .LifecycleName}}
+	*{{.LifecycleName}}
+{{end}}
+{{range .Fields}}	{{.Name}} {{.Type}}
+{{end}}}
+
+// {{.BuildName}} constructs the package dependency graph in dependency order, taking the types
+// no constructor of the package provides as parameters.
+func {{.BuildName}}({{.Params}}) (*{{.ContainerName}}, error) {
+	var err error
+	c := &{{.ContainerName}}{ {{- if .LifecycleName}}{{.LifecycleName}}: &{{.LifecycleName}}{}{{end -}} }
+{{range .Steps}}
+{{- if .Fallible}}
+	c.{{.Field}}, err = {{.Constructor}}({{.Args}})
+	if err != nil {
+		return nil, err
+	}
+{{- else}}
+	c.{{.Field}} = {{.Constructor}}({{.Args}})
+{{- end}}
+{{- end}}
+
+	return c, err
+}
+{{- if .LifecycleName}}
+
+// {{.LifecycleName}} collects the hooks appended by the constructors, in place of the fx application lifecycle.
+type {{.LifecycleName}} struct {
+	hooks   []{{.HookType}}
+	started int
+}
+
+func (l *{{.LifecycleName}}) Append(h {{.HookType}}) {
+	l.hooks = append(l.hooks, h)
+}
+
+// Start runs the OnStart hooks in order, stopping at the first error.
+func (l *{{.LifecycleName}}) Start(ctx {{.Context}}.Context) error {
+	for ; l.started < len(l.hooks); l.started++ {
+		if h := l.hooks[l.started]; h.OnStart != nil {
+			if err := h.OnStart(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Stop runs the OnStop hooks of the started ones in reverse order, returning the first error.
+func (l *{{.LifecycleName}}) Stop(ctx {{.Context}}.Context) error {
+	var err error
+	for ; l.started > 0; l.started-- {
+		if h := l.hooks[l.started-1]; h.OnStop != nil {
+			if stopErr := h.OnStop(ctx); stopErr != nil && err == nil {
+				err = stopErr
+			}
+		}
+	}
+	return err
+}
+{{- end}}
+`
+)
@@ -0,0 +1,60 @@
+package template
+
+// TestData is the data of the TestHarness template, naming the test functions and the function supplying
+// stand-ins for the requirements.
+type TestData struct {
+	PackageName      string
+	ModuleName       string
+	ValidationName   string
+	ProvidesName     string
+	RequirementsName string
+	Imports          []Import
+	Provided         []string
+	Requirements     []string
+}
+
+const (
+	TestHarness = `// Code generated by autofx. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+
+func {{.ValidationName}}(t *testing.T) {
+	err := fx.ValidateApp(
+		{{.ModuleName}}(),
+		{{.RequirementsName}}(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func {{.ProvidesName}}(t *testing.T) {
+	var (
+	{{range $i, $p := .Provided}}	v{{$i}} {{$p}}
+	{{end}})
+
+	app := fxtest.New(t,
+		{{.ModuleName}}(),
+		{{.RequirementsName}}(),
+		fx.Populate(
+		{{range $i, $p := .Provided}}	&v{{$i}},
+		{{end}}),
+	)
+	app.RequireStart().RequireStop()
+}
+
+// {{.RequirementsName}} supplies stand-ins for the types the module requires from outside the package.
+func {{.RequirementsName}}() fx.Option {
+	return fx.Options(
+	{{range .Requirements}}	fx.Provide(func() {{.}} {
+			var v {{.}}
+			return v
+		}),
+	{{end}})
+}
+`
+)
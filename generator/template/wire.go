@@ -0,0 +1,23 @@
+package template
+
+type WireData struct {
+	PackageName string
+	Imports     []Import
+	Providers   []string
+}
+
+const (
+	WireSet = `// Code generated by autofx. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+
+// ProviderSet provides every constructor of the package along with its interface bindings.
+var ProviderSet = wire.NewSet(
+{{range .Providers}}	{{.}},
+{{end}})
+`
+)
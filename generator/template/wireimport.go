@@ -0,0 +1,70 @@
+package template
+
+import "github.com/jsperandio/autofx/analyzer/definition"
+
+type WireStructData struct {
+	Type        string
+	Constructor string
+	Params      string
+	Fields      []string
+}
+
+type WireModuleData struct {
+	Name       string
+	ModuleName string
+	Sets       []string
+	Providers  []string
+	Bindings   []definition.WireBinding
+	Structs    []WireStructData
+	Values     []string
+}
+
+type WireImportData struct {
+	PackageName string
+	Imports     []Import
+	Modules     []WireModuleData
+}
+
+const (
+	WireImport = `// Code generated by autofx. DO NOT EDIT.
+// Translated from the google/wire provider sets by the import-wire command.
+
+package {{.PackageName}}
+
+import (
+{{range .Imports}}	{{if .Alias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}})
+{{range .Modules}}
+// {{.ModuleName}} is the fx translation of the {{.Name}} wire provider set.
+func {{.ModuleName}}() fx.Option {
+	return fx.Options(
+{{- range .Sets}}
+		{{.}}(),
+{{- end}}
+{{- range .Providers}}
+		fx.Provide({{.}}),
+{{- end}}
+{{- range .Structs}}
+		fx.Provide({{.Constructor}}),
+		fx.Provide(func(v *{{.Type}}) {{.Type}} { return *v }),
+{{- end}}
+{{- range .Bindings}}
+		fx.Provide(func(v {{.Implementation}}) {{.Interface}} { return v }),
+{{- end}}
+{{- range .Values}}
+		fx.Supply({{.}}),
+{{- end}}
+	)
+}
+{{range .Structs}}
+// {{.Constructor}} builds a {{.Type}} from its injected fields, as wire.Struct does.
+func {{.Constructor}}({{.Params}}) *{{.Type}} {
+	return &{{.Type}}{
+{{- range .Fields}}
+		{{.}},
+{{- end}}
+	}
+}
+{{end}}
+{{- end}}`
+)
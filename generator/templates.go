@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/log"
+)
+
+// templateNames are the names of the templates the options can override, as documented in the template package.
+var templateNames = []string{
+	"GoFileInits", "ProviderModule", "PackageModule", "InterfaceAssertions", "TestHarness",
+	"FakeFileInits", "Fake", "TestModule", "WireSet", "PlainBuild", "WireImport", "ConfigLoader", "AppMain",
+	"RequirementsContract", "GroupRecipe", "Decorator", "RootDecorators", "Selector", "Registry",
+}
+
+// templateAliases maps the former template names to the template they now override.
+var templateAliases = map[string]string{
+	"SimpleModule":    "ProviderModule",
+	"InterfaceModule": "ProviderModule",
+}
+
+// templateFuncs is the helper function library available to every template, documented in the template package.
+var templateFuncs = template.FuncMap{
+	"qualify":    qualify,
+	"baseType":   func(typ string) string { return strings.TrimLeft(typ, "*") },
+	"lowerCamel": lowerCamel,
+	"upperCamel": upperCamel,
+	"join":       func(list []string, sep string) string { return strings.Join(list, sep) },
+	"hasPrefix":  strings.HasPrefix,
+	"trimPrefix": strings.TrimPrefix,
+	"params":     func(list []definition.Param) string { return stringfyParams(list) },
+}
+
+// checkTemplates rejects the templates of the options matching no template, and the templates overridden
+// under several names.
+func checkTemplates(opts Options) error {
+	overridden := make(map[string]string)
+	for _, name := range sortedKeys(opts.Templates) {
+		target := name
+		if alias, ok := templateAliases[name]; ok {
+			target = alias
+		}
+		if !slices.Contains(templateNames, target) {
+			return fmt.Errorf("template %s matches no template, known ones are %s", name, strings.Join(templateNames, ", "))
+		}
+		if other, ok := overridden[target]; ok {
+			return fmt.Errorf("templates %s and %s both override %s", other, name, target)
+		}
+		overridden[target] = name
+	}
+	return nil
+}
+
+// loadTemplate parses the template registered by name, preferring the file referenced in the options,
+// then a <name>.tmpl file in the template directory, then the given default source.
+// The former names of the template are looked up after its own.
+func loadTemplate(opts Options, name string, def string) (*template.Template, error) {
+	src := def
+
+	names := []string{name}
+	for _, alias := range sortedKeys(templateAliases) {
+		if templateAliases[alias] == name {
+			names = append(names, alias)
+		}
+	}
+
+	path := templatePath(opts, names)
+
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		src = string(b)
+	}
+
+	t, err := template.New(name).Funcs(templateFuncs).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// templatePath returns the file overriding the template known by the given names, if any.
+func templatePath(opts Options, names []string) string {
+	for i, n := range names {
+		if path, ok := opts.Templates[n]; ok {
+			if i > 0 {
+				log.Warnf("template %s is deprecated, override %s instead", n, names[0])
+			}
+			return path
+		}
+	}
+
+	if opts.TemplateDir == "" {
+		return ""
+	}
+	for i, n := range names {
+		path := filepath.Join(opts.TemplateDir, n+".tmpl")
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if i > 0 {
+			log.Warnf("template %s is deprecated, override %s instead", n, names[0])
+		}
+		return path
+	}
+	return ""
+}
+
+// qualify prefixes a type declared in the package with the package name, keeping its pointer,
+// slice and variadic notation. Predeclared and already qualified types are left untouched.
+func qualify(typ string, pkg string) string {
+	base := strings.TrimLeft(typ, "*[].")
+	prefix := typ[:len(typ)-len(base)]
+	if pkg == "" || base == "" || strings.Contains(base, ".") || !unicode.IsUpper(rune(base[0])) {
+		return typ
+	}
+	return fmt.Sprintf("%s%s.%s", prefix, pkg, base)
+}
+
+// lowerCamel lower cases the first letter of a name.
+func lowerCamel(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// upperCamel upper cases the first letter of a name.
+func upperCamel(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// stringfyParams formats a parameter list as it is declared in Go code.
+func stringfyParams(list []definition.Param) string {
+	params := make([]string, len(list))
+	for i, p := range list {
+		params[i] = p.String()
+	}
+	return strings.Join(params, ", ")
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates map[string]string
+		err       string
+	}{
+		{name: "none"},
+		{name: "known", templates: map[string]string{"ProviderModule": "p.tmpl", "PlainBuild": "b.tmpl"}},
+		{name: "former name", templates: map[string]string{"SimpleModule": "s.tmpl"}},
+		{name: "unknown", templates: map[string]string{"ProvidersModule": "p.tmpl"}, err: "template ProvidersModule matches no template"},
+		{name: "overridden twice", templates: map[string]string{"InterfaceModule": "i.tmpl", "SimpleModule": "s.tmpl"}, err: "templates InterfaceModule and SimpleModule both override ProviderModule"},
+		{name: "overridden by both names", templates: map[string]string{"ProviderModule": "p.tmpl", "SimpleModule": "s.tmpl"}, err: "both override ProviderModule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTemplates(Options{Templates: tt.templates})
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("error %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"PackageModule.tmpl": "dir {{lowerCamel .}}",
+		"configured.tmpl":    "configured {{upperCamel .}}",
+		"invalid.tmpl":       "{{.Name",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+		err  string
+	}{
+		{name: "default", opts: Options{}, want: "default UserDB"},
+		{name: "template directory", opts: Options{TemplateDir: dir}, want: "dir userDB"},
+		{name: "directory without the template", opts: Options{TemplateDir: t.TempDir()}, want: "default UserDB"},
+		{
+			name: "configured file before the directory",
+			opts: Options{TemplateDir: dir, Templates: map[string]string{"PackageModule": filepath.Join(dir, "configured.tmpl")}},
+			want: "configured UserDB",
+		},
+		{
+			name: "missing configured file",
+			opts: Options{Templates: map[string]string{"PackageModule": filepath.Join(dir, "missing.tmpl")}},
+			err:  "template PackageModule: open",
+		},
+		{
+			name: "invalid template",
+			opts: Options{Templates: map[string]string{"PackageModule": filepath.Join(dir, "invalid.tmpl")}},
+			err:  "template PackageModule: template: PackageModule:1: unclosed action",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := loadTemplate(tt.opts, "PackageModule", "default {{.}}")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			err = tpl.Execute(&b, "UserDB")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("loaded %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTemplateFormerNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"SimpleModule", "InterfaceModule"} {
+		err := os.WriteFile(filepath.Join(dir, name+".tmpl"), []byte(name), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "default", opts: Options{}, want: "default"},
+		{name: "configured former name", opts: Options{Templates: map[string]string{"SimpleModule": filepath.Join(dir, "SimpleModule.tmpl")}}, want: "SimpleModule"},
+		{name: "former name in the template directory", opts: Options{TemplateDir: dir}, want: "InterfaceModule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl, err := loadTemplate(tt.opts, "ProviderModule", "default")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			err = tpl.Execute(&b, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("loaded %s, want %s", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package api is built as example.com/app/api, copied into a module of that name by the app tests.
+package api
+
+import (
+	"log"
+
+	"example.com/app/store"
+)
+
+type Server struct {
+	store  store.Store
+	logger *log.Logger
+}
+
+func NewServer(s store.Store, logger *log.Logger) *Server {
+	return &Server{store: s, logger: logger}
+}
+
+//autofx:invoke
+func Run(s *Server) {
+	s.logger.Print(s.store.Get("ready"))
+}
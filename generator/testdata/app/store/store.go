@@ -0,0 +1,11 @@
+package store
+
+type Store interface {
+	Get(key string) string
+}
+
+type MemStore struct{}
+
+func NewMemStore() *MemStore { return &MemStore{} }
+
+func (m *MemStore) Get(key string) string { return key }
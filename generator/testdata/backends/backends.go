@@ -0,0 +1,29 @@
+package backends
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+)
+
+type Store interface {
+	Get(key string) string
+}
+
+type DB struct{}
+
+func NewDB(lc fx.Lifecycle) *DB {
+	db := &DB{}
+	lc.Append(fx.Hook{OnStart: func(context.Context) error { return nil }})
+	return db
+}
+
+func (d *DB) Get(key string) string { return key }
+
+type Service struct{ store Store }
+
+func NewService(s Store) *Service { return &Service{store: s} }
+
+type Handler struct{ svc *Service }
+
+func NewHandler(svc *Service, name string) (*Handler, error) { return &Handler{svc: svc}, nil }
@@ -0,0 +1,66 @@
+package decorators
+
+import (
+	"errors"
+	"io"
+)
+
+// Store is decorated at the root of the application.
+//
+//autofx:decorate
+type Store interface {
+	io.Closer
+	Get(key string) (string, error)
+	Len() int
+	Delete(keys ...string) error
+}
+
+type MemStore struct{ data map[string]string }
+
+func NewMemStore() *MemStore { return &MemStore{data: map[string]string{"a": "1"}} }
+
+func (s *MemStore) Close() error { return nil }
+
+func (s *MemStore) Get(key string) (string, error) {
+	v, ok := s.data[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func (s *MemStore) Len() int { return len(s.data) }
+
+func (s *MemStore) Delete(keys ...string) error {
+	for _, k := range keys {
+		delete(s.data, k)
+	}
+	return nil
+}
+
+// Cache is decorated within the package module.
+//
+//autofx:private
+//autofx:decorate
+type Cache interface {
+	Lookup(key string) string
+}
+
+type MemCache struct{}
+
+func NewMemCache() *MemCache { return &MemCache{} }
+
+func (c *MemCache) Lookup(key string) string { return key }
+
+// Notifier is bound by no provider of the package.
+//
+//autofx:decorate
+type Notifier interface {
+	Notify(msg string)
+}
+
+type Service struct {
+	Cache Cache
+}
+
+func NewService(c Cache) *Service { return &Service{Cache: c} }
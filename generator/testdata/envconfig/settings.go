@@ -0,0 +1,16 @@
+package envconfig
+
+import "time"
+
+type ServerConfig struct {
+	Addr    string        `env:"SERVER_ADDR" default:":8080"`
+	Port    int           `env:"SERVER_PORT" default:"8080"`
+	Workers uint8         `env:"SERVER_WORKERS"`
+	Ratio   float32       `env:"SERVER_RATIO" default:"0.5"`
+	Timeout time.Duration `env:"SERVER_TIMEOUT" default:"5s"`
+	Debug   bool          `env:"SERVER_DEBUG"`
+	Hosts   []string      `env:"SERVER_HOSTS"`
+	Token   string        `env:"SERVER_TOKEN" required:"true"`
+	// Name is required, but satisfied by its empty default.
+	Name string `env:"SERVER_NAME" default:"" required:"true"`
+}
@@ -0,0 +1,16 @@
+package framework
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	"go.uber.org/fx"
+)
+
+type Server struct{}
+
+func NewServer(ctx context.Context, lc fx.Lifecycle, logger *slog.Logger, client *http.Client, db *sql.DB) *Server {
+	return &Server{}
+}
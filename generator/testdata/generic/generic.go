@@ -0,0 +1,17 @@
+package generic
+
+type User struct{}
+
+//autofx:instantiate User
+type Repo[T any] struct{ items []T }
+
+func NewRepo[T any]() *Repo[T] { return &Repo[T]{} }
+
+// RepoUser is named like the Repo[User] instantiation.
+type RepoUser struct{}
+
+func NewRepoUser() *RepoUser { return &RepoUser{} }
+
+type Svc struct{}
+
+func NewSvc(r *Repo[User], u *RepoUser) *Svc { return &Svc{} }
@@ -0,0 +1,19 @@
+package named
+
+import "time"
+
+type Clock func() time.Time
+
+func NewClock() Clock { return time.Now }
+
+type Port int
+
+func NewPort() Port { return 8080 }
+
+type Hosts []string
+
+func NewHosts() Hosts { return Hosts{"localhost"} }
+
+type Server struct{}
+
+func NewServer(c Clock, p Port, h Hosts) *Server { return &Server{} }
@@ -0,0 +1,19 @@
+package private
+
+// Store reads records by key.
+type Store interface {
+	Get(key string) string
+}
+
+// DiskStore is only reachable through Store outside of the package module.
+//
+//autofx:private
+type DiskStore struct{}
+
+func NewDiskStore() *DiskStore {
+	return &DiskStore{}
+}
+
+func (s *DiskStore) Get(key string) string {
+	return key
+}
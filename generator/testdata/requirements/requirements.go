@@ -0,0 +1,20 @@
+package requirements
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+type Repo struct{ db *sql.DB }
+
+func NewRepo(db *sql.DB) *Repo { return &Repo{db: db} }
+
+type Handler struct {
+	repo   *Repo
+	client *http.Client
+	db     *sql.DB
+}
+
+func NewHandler(repo *Repo, client *http.Client, db *sql.DB) *Handler {
+	return &Handler{repo: repo, client: client, db: db}
+}
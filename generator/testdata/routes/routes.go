@@ -0,0 +1,18 @@
+package routes
+
+import "net/http"
+
+type Health struct{}
+
+func NewHealth() *Health { return &Health{} }
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (h *Health) Pattern() string { return "/health" }
+
+// Users serves HTTP without telling its pattern.
+type Users struct{}
+
+func NewUsers() *Users { return &Users{} }
+
+func (u *Users) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
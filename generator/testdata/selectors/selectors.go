@@ -0,0 +1,25 @@
+package selectors
+
+type StoreConfig struct {
+	Kind    string `env:"SELECTORS_STORE_KIND"`
+	Retries int    `env:"SELECTORS_STORE_RETRIES"`
+}
+
+// Store is selected by the Kind of the StoreConfig.
+//
+//autofx:select StoreConfig.Kind
+type Store interface {
+	Name() string
+}
+
+type MemStore struct{}
+
+func NewMemStore() *MemStore { return &MemStore{} }
+
+func (s *MemStore) Name() string { return "mem" }
+
+type DiskStore struct{}
+
+func NewDiskStore() *DiskStore { return &DiskStore{} }
+
+func (s *DiskStore) Name() string { return "disk" }
@@ -0,0 +1,7 @@
+package shutdowner
+
+import "go.uber.org/fx"
+
+type Stopper struct{ s fx.Shutdowner }
+
+func NewStopper(s fx.Shutdowner) *Stopper { return &Stopper{s: s} }
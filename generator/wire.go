@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+
+	tmpl "github.com/jsperandio/autofx/generator/template"
+	"github.com/jsperandio/autofx/log"
+)
+
+const wireFileName = "wire_set.go"
+
+// wireBackend emits a google/wire provider set binding every interface to its provider.
+type wireBackend struct {
+	opts Options
+}
+
+func newWireBackend(opts Options) Backend {
+	return &wireBackend{
+		opts: opts,
+	}
+}
+
+func (b *wireBackend) Generate(g *Graph) ([]*File, error) {
+	imports := g.Package.ImportsOf(g.Instantiated())
+	imports["wire"] = "github.com/google/wire"
+	wd := tmpl.WireData{
+		PackageName: g.Package.Name,
+		Imports:     importList(imports),
+		Providers:   make([]string, 0),
+	}
+
+	for _, p := range g.Providers {
+		wd.Providers = append(wd.Providers, p.Constructor.Name)
+		for _, ifc := range p.Interfaces {
+			wd.Providers = append(wd.Providers, fmt.Sprintf("wire.Bind(new(%s), new(%s))", ifc, p.Type))
+		}
+	}
+	// wire resolves at compile time, so selected interfaces are bound to their default implementation.
+	for _, s := range g.Selectors {
+		log.Warnf("interface %s is selected at runtime with the fx backend only, bound to %s", s.Interface, s.Default)
+		p := g.ProviderOf(s.Interface)
+		wd.Providers = append(wd.Providers, fmt.Sprintf("wire.Bind(new(%s), new(%s))", s.Interface, p.Type))
+	}
+
+	f := NewFile(wireFileName, g.Package.Path, nil)
+	t, err := loadTemplate(b.opts, "WireSet", tmpl.WireSet)
+	if err != nil {
+		return nil, err
+	}
+	err = t.Execute(f, wd)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*File{f}, nil
+}
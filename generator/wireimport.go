@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	tmpl "github.com/jsperandio/autofx/generator/template"
+)
+
+const wireImportFileName = "wire_module.go"
+
+// WireImporter translates the google/wire provider sets of a package into fx modules.
+type WireImporter struct {
+	Package *definition.Package
+	Options Options
+}
+
+// NewWireImporter returns a new WireImporter instance for the given package.
+func NewWireImporter(pkg *definition.Package, opts Options) *WireImporter {
+	if pkg == nil {
+		return nil
+	}
+
+	return &WireImporter{
+		Package: pkg,
+		Options: opts,
+	}
+}
+
+// Import builds a file with an fx module for each provider set, and reports the wire calls left untranslated.
+// The file is formatted but not saved.
+func (w *WireImporter) Import() (*File, []string, error) {
+	if len(w.Package.WireSets) == 0 {
+		return nil, nil, fmt.Errorf("no wire provider sets found in package %s", w.Package.Name)
+	}
+	err := checkTemplates(w.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wid := tmpl.WireImportData{
+		PackageName: w.Package.Name,
+		Modules:     make([]tmpl.WireModuleData, 0, len(w.Package.WireSets)),
+	}
+
+	names := newNamer(w.Options.Naming, w.Package.Identifiers)
+	moduleNames := make(map[string]string, len(w.Package.WireSets))
+	for _, name := range sortedKeys(w.Package.WireSets) {
+		moduleNames[name] = names.module(name)
+	}
+
+	var report []string
+	var used []definition.Param
+	for _, name := range sortedKeys(w.Package.WireSets) {
+		set := w.Package.WireSets[name]
+		report = append(report, set.Unsupported...)
+
+		md := tmpl.WireModuleData{
+			Name:       set.Name,
+			ModuleName: moduleNames[name],
+			Providers:  set.Providers,
+			Bindings:   set.Bindings,
+			Values:     set.Values,
+			Sets:       make([]string, len(set.Sets)),
+			Structs:    make([]tmpl.WireStructData, len(set.Structs)),
+		}
+
+		for i, s := range set.Sets {
+			md.Sets[i] = moduleNames[s]
+		}
+
+		for i, s := range set.Structs {
+			md.Structs[i] = wireStructData(s)
+			used = append(used, s.Fields...)
+		}
+
+		for _, b := range set.Bindings {
+			used = append(used, definition.Param{Type: b.Interface}, definition.Param{Type: b.Implementation})
+		}
+		for _, e := range append(set.Providers, set.Values...) {
+			used = append(used, definition.Param{Type: e})
+		}
+
+		wid.Modules = append(wid.Modules, md)
+	}
+
+	imports := w.Package.ImportsOf(used)
+	imports["fx"] = "go.uber.org/fx"
+	wid.Imports = importList(imports)
+
+	t, err := loadTemplate(w.Options, "WireImport", tmpl.WireImport)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := NewFile(wireImportFileName, w.Package.Path, nil)
+	err = t.Execute(f, wid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = f.Format()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, report, nil
+}
+
+// Bindings returns the wire.Bind calls of the provider sets as options bindings, from interface to struct,
+// reporting the ones the options cannot express: types declared in another package and interfaces bound
+// to several structs.
+func (w *WireImporter) Bindings() (map[string]string, []string) {
+	bindings := make(map[string]string)
+	var report []string
+	for _, name := range sortedKeys(w.Package.WireSets) {
+		for _, b := range w.Package.WireSets[name].Bindings {
+			impl := strings.TrimPrefix(b.Implementation, "*")
+			_, isIfc := w.Package.Interfaces[b.Interface]
+			_, isStruct := w.Package.Structs[impl]
+			if !isIfc || !isStruct {
+				report = append(report, fmt.Sprintf("binding of %s to %s in %s, not declared by the package", b.Interface, b.Implementation, name))
+				continue
+			}
+
+			if other, ok := bindings[b.Interface]; ok && other != impl {
+				report = append(report, fmt.Sprintf("binding of %s to %s in %s, already bound to %s", b.Interface, b.Implementation, name, other))
+				continue
+			}
+			bindings[b.Interface] = impl
+		}
+	}
+	return bindings, report
+}
+
+// wireStructData builds the template data of the constructor replacing a wire.Struct call.
+func wireStructData(s definition.WireStruct) tmpl.WireStructData {
+	params := make([]string, len(s.Fields))
+	fields := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		params[i] = fmt.Sprintf("p%d %s", i, f.Type)
+		fields[i] = fmt.Sprintf("%s: p%d", f.Name, i)
+	}
+
+	return tmpl.WireStructData{
+		Type:        s.Type,
+		Constructor: "newWire" + upperCamel(strings.ReplaceAll(s.Type, ".", "")),
+		Params:      strings.Join(params, ", "),
+		Fields:      fields,
+	}
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/analyzer/parser"
+)
+
+func TestWireImportGenerated(t *testing.T) {
+	pkg := definition.NewPackage("store", t.TempDir())
+	set := definition.NewWireSet("StoreSet")
+	set.Providers = []string{"NewDB"}
+	pkg.WireSets["StoreSet"] = set
+
+	f, _, err := NewWireImporter(pkg, Options{}).Import()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(f.Content, []byte(parser.GeneratedHeader)) {
+		t.Fatalf("translation not marked as generated:\n%s", f.Content)
+	}
+
+	err = SaveGenerated(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveGenerated(f)
+	if err != nil || f.Name != wireImportFileName {
+		t.Errorf("translation saved again as %s, error %v", f.Name, err)
+	}
+}
+
+func TestWireImporterBindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string][]definition.WireBinding
+		want     map[string]string
+		report   []string
+	}{
+		{
+			name:     "package types",
+			bindings: map[string][]definition.WireBinding{"Set": {{Interface: "Store", Implementation: "*DB"}}},
+			want:     map[string]string{"Store": "DB"},
+		},
+		{
+			name: "same binding in several sets",
+			bindings: map[string][]definition.WireBinding{
+				"ASet": {{Interface: "Store", Implementation: "*DB"}},
+				"BSet": {{Interface: "Store", Implementation: "DB"}},
+			},
+			want: map[string]string{"Store": "DB"},
+		},
+		{
+			name: "interface bound to several structs",
+			bindings: map[string][]definition.WireBinding{
+				"ASet": {{Interface: "Store", Implementation: "*DB"}},
+				"BSet": {{Interface: "Store", Implementation: "*Mem"}},
+			},
+			want:   map[string]string{"Store": "DB"},
+			report: []string{"binding of Store to *Mem in BSet, already bound to DB"},
+		},
+		{
+			name:     "types of another package",
+			bindings: map[string][]definition.WireBinding{"Set": {{Interface: "io.Writer", Implementation: "*DB"}}},
+			want:     map[string]string{},
+			report:   []string{"binding of io.Writer to *DB in Set, not declared by the package"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := definition.NewPackage("store", "store")
+			pkg.Interfaces["Store"] = definition.NewInterface("Store")
+			pkg.Structs["DB"] = definition.NewStruct("DB")
+			pkg.Structs["Mem"] = definition.NewStruct("Mem")
+			for name, bindings := range tt.bindings {
+				set := definition.NewWireSet(name)
+				set.Bindings = bindings
+				pkg.WireSets[name] = set
+			}
+
+			got, report := NewWireImporter(pkg, Options{}).Bindings()
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("bindings %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(report, tt.report) {
+				t.Errorf("report %s, want %s", strings.Join(report, "; "), strings.Join(tt.report, "; "))
+			}
+		})
+	}
+}
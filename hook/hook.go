@@ -0,0 +1,71 @@
+// Package hook is the runtime side of the decorators generated by autofx: every call of a decorated
+// interface method is reported to a Hook before and after delegating to the decorated value.
+//
+// Provide a Hook to the application to enable the decorators, like fx.Provide(func() hook.Hook { return hook.Funcs{...} }).
+// Decorators are left out when no Hook is provided.
+//
+// fx.Decorate only applies to the module it is given to and to the modules nested in it. The decorators of
+// public interfaces are therefore applied by the Decorators option of each generated package, to be included
+// at the root of the application, as the app command does, for every consumer to get the decorated value.
+// Left out, no consumer outside the package is decorated. Private interfaces are decorated within their package module.
+package hook
+
+import "time"
+
+// Hook observes the calls of decorated interface methods.
+type Hook interface {
+	Before(c *Call)
+	After(c *Call)
+}
+
+// Call describes a call of a decorated interface method.
+type Call struct {
+	Interface string
+	Method    string
+	Args      []any
+	Start     time.Time
+	// Duration is set once the call returns.
+	Duration time.Duration
+	// Err is set once the call returns, to the last result of methods returning an error.
+	Err error
+
+	hook Hook
+}
+
+// Begin reports the start of a call to the hook, returning the call to End once it returns.
+func Begin(h Hook, iface, method string, args ...any) *Call {
+	c := &Call{
+		Interface: iface,
+		Method:    method,
+		Args:      args,
+		hook:      h,
+	}
+	h.Before(c)
+	c.Start = time.Now()
+	return c
+}
+
+// End reports the end of the call to its hook, along with the error it returned, if any.
+func (c *Call) End(err error) {
+	c.Duration = time.Since(c.Start)
+	c.Err = err
+	c.hook.After(c)
+}
+
+// Funcs is a Hook calling its functions, either of them being optional.
+type Funcs struct {
+	BeforeFunc func(c *Call)
+	AfterFunc  func(c *Call)
+}
+
+func (f Funcs) Before(c *Call) {
+	if f.BeforeFunc != nil {
+		f.BeforeFunc(c)
+	}
+}
+
+func (f Funcs) After(c *Call) {
+	if f.AfterFunc != nil {
+		f.AfterFunc(c)
+	}
+}
@@ -0,0 +1,71 @@
+package hook
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBeginEnd(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name string
+		args []any
+		err  error
+	}{
+		{name: "no args", err: nil},
+		{name: "args", args: []any{"key", 1}, err: nil},
+		{name: "error", args: []any{"key"}, err: errFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+			var before, after *Call
+			h := Funcs{
+				BeforeFunc: func(c *Call) {
+					events = append(events, "before")
+					before = c
+				},
+				AfterFunc: func(c *Call) {
+					events = append(events, "after")
+					after = c
+				},
+			}
+
+			c := Begin(h, "Store", "Get", tt.args...)
+			if !slices.Equal(events, []string{"before"}) {
+				t.Fatalf("events after Begin = %v", events)
+			}
+			if c.Interface != "Store" || c.Method != "Get" || !slices.Equal(c.Args, tt.args) {
+				t.Errorf("call = %+v", c)
+			}
+			if c.Start.IsZero() {
+				t.Error("start not set")
+			}
+
+			c.End(tt.err)
+			if !slices.Equal(events, []string{"before", "after"}) {
+				t.Fatalf("events after End = %v", events)
+			}
+			if before != c || after != c {
+				t.Error("hook not given the call")
+			}
+			if !errors.Is(c.Err, tt.err) {
+				t.Errorf("err = %v, want %v", c.Err, tt.err)
+			}
+			if c.Duration < 0 {
+				t.Errorf("duration = %v", c.Duration)
+			}
+		})
+	}
+}
+
+func TestFuncsOptional(t *testing.T) {
+	c := Begin(Funcs{}, "Store", "Put")
+	c.End(nil)
+	if c.Err != nil {
+		t.Errorf("err = %v", c.Err)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/jsperandio/autofx/analyzer/definition"
+	"github.com/jsperandio/autofx/config"
+	"github.com/jsperandio/autofx/generator"
+	"github.com/jsperandio/autofx/log"
+)
+
+// importWire runs the import-wire command, writing the fx translation of the package wire provider sets
+// along with their bindings into the configuration file, and reporting the calls left untranslated.
+func importWire(def *definition.Package) {
+	cfg := loadConfig()
+
+	wi := generator.NewWireImporter(def, cfg.Generator)
+	f, report, err := wi.Import()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bindings, unbound := wi.Bindings()
+	for _, r := range unbound {
+		log.Warnf("not configured %s", r)
+	}
+	if len(bindings) > 0 {
+		kept, err := config.AddBindings(configPath(), bindings)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, ifc := range kept {
+			log.Warnf("not configured binding of %s to %s, already bound to another struct", ifc, bindings[ifc])
+		}
+		log.Infof("wire bindings added to %s", configPath())
+	}
+
+	for _, r := range report {
+		log.Warnf("not translated %s", r)
+	}
+
+	err = generator.SaveGenerated(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("wire provider sets translated to %s/%s", f.Path, f.Name)
+}
@@ -16,6 +16,22 @@ func Error(args ...interface{}) {
 	instance.Error(args...)
 }
 
+func Errorf(template string, args ...interface{}) {
+	instance.Errorf(template, args...)
+}
+
 func Fatal(args ...interface{}) {
 	instance.Fatal(args...)
 }
+
+func Info(args ...interface{}) {
+	instance.Info(args...)
+}
+
+func Infof(template string, args ...interface{}) {
+	instance.Infof(template, args...)
+}
+
+func Warnf(template string, args ...interface{}) {
+	instance.Warnf(template, args...)
+}
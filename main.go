@@ -2,22 +2,56 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/jsperandio/autofx/analyzer"
-	"github.com/jsperandio/autofx/example"
+	"github.com/jsperandio/autofx/config"
 	"github.com/jsperandio/autofx/generator"
 	"github.com/jsperandio/autofx/log"
-	"go.uber.org/fx"
 )
 
 var (
 	pkgPathFlag  *string
 	logLevelFlag *string
+	cmdFlag      *string
+	configFlag   *string
+	backendFlag  *string
+	assertFlag   *bool
+	testFlag     *bool
+	fakesFlag    *bool
+	manifestFlag *bool
+	registryFlag *bool
+
+	structFlag   *string
+	ifaceFlag    *string
+	methodsFlag  *string
+	consumerFlag *string
+	writeFlag    *bool
+
+	outFlag *string
 )
 
 func flagParse() {
 	pkgPathFlag = flag.String("p", "", "package path")
 	logLevelFlag = flag.String("ll", "info", "log level")
+	cmdFlag = flag.String("cmd", "generate", "command to run (generate, extract, import-wire, app)")
+	configFlag = flag.String("cfg", "", fmt.Sprintf("configuration file, defaults to %s in the package path", config.DefaultFileName))
+	backendFlag = flag.String("b", "fx", fmt.Sprintf("backend emitting the wiring code (%s) (generate)", strings.Join(generator.Backends(), ", ")))
+	assertFlag = flag.Bool("assert", false, "emit compile-time assertions for interface bindings (generate)")
+	testFlag = flag.Bool("test", false, "emit a module_test.go validating the generated module (generate)")
+	fakesFlag = flag.Bool("fakes", false, "emit fakes of the package interfaces and a TestModule (generate)")
+	manifestFlag = flag.Bool("manifest", false, "emit a requirements.json listing the types required from outside (generate)")
+	registryFlag = flag.Bool("registry", false, "emit a Register function adding the providers to a compose.Registry (generate)")
+
+	structFlag = flag.String("s", "", "struct to extract the interface from (extract)")
+	ifaceFlag = flag.String("i", "", "name of the extracted interface (extract)")
+	methodsFlag = flag.String("m", "", "comma separated methods of the extracted interface (extract)")
+	consumerFlag = flag.String("c", "", "consumer constructor whose calls define the extracted methods (extract)")
+	writeFlag = flag.Bool("w", false, "write the extracted interface into the package (extract)")
+
+	outFlag = flag.String("o", "", "directory of the composition root, defaults to cmd/app in the package path (app)")
 	flag.Parse()
 
 	if *pkgPathFlag == "" {
@@ -29,12 +63,27 @@ func flagParse() {
 		log.Error("flag [-ll] is invalid")
 		panic("Invalid log level")
 	}
+
+	if *cmdFlag != "generate" && *cmdFlag != "extract" && *cmdFlag != "import-wire" && *cmdFlag != "app" {
+		log.Error("flag [-cmd] is invalid")
+		panic("Invalid command")
+	}
+
+	if *cmdFlag == "extract" && (*structFlag == "" || *ifaceFlag == "") {
+		log.Error("flags [-s] and [-i] are required by extract")
+		panic("Struct and interface names are required")
+	}
 }
 
 func main() {
 	flagParse()
 	log.Init(logLevelFlag)
 
+	if *cmdFlag == "app" {
+		app()
+		return
+	}
+
 	ins := analyzer.NewInspector()
 	def, err := ins.InspectPackage(*pkgPathFlag)
 	if err != nil {
@@ -42,15 +91,60 @@ func main() {
 	}
 	// def.Print()
 
-	gen := generator.NewGenerator(def)
+	switch *cmdFlag {
+	case "extract":
+		extract(def)
+		return
+	case "import-wire":
+		importWire(def)
+		return
+	}
+
+	cfg := loadConfig()
+
+	gen := generator.NewGenerator(def, cfg.Generator)
 	err = gen.Generate()
 	if err != nil {
 		log.Error(err)
 	}
+}
+
+// loadConfig loads the configuration file, overriding it with the flags explicitly set.
+func loadConfig() *config.Config {
+	return loadConfigFile(configPath())
+}
+
+// configPath returns the path of the configuration file, given by flag or else in the package path.
+func configPath() string {
+	if *configFlag != "" {
+		return *configFlag
+	}
+	return filepath.Join(*pkgPathFlag, config.DefaultFileName)
+}
+
+// loadConfigFile loads the configuration file at path, overriding it with the flags explicitly set.
+func loadConfigFile(path string) *config.Config {
+	cfg, err := config.Load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "b":
+			cfg.Generator.Backend = *backendFlag
+		case "assert":
+			cfg.Generator.Assertions = *assertFlag
+		case "test":
+			cfg.Generator.TestHarness = *testFlag
+		case "fakes":
+			cfg.Generator.Fakes = *fakesFlag
+		case "manifest":
+			cfg.Generator.Manifest = *manifestFlag
+		case "registry":
+			cfg.Generator.Registry = *registryFlag
+		}
+	})
 
-	app := fx.New(
-		example.Module(),
-		fx.Invoke(example.Run),
-	)
-	app.Run()
+	return cfg
 }